@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/silenceper/mcp-k8s/internal/config"
@@ -18,6 +19,12 @@ var (
 	enableUpdate          bool
 	enableDelete          bool
 	enableList            bool
+	enableApply           bool
+	enableExec            bool
+	enablePortForward     bool
+	copyDir               string
+	listMaxResponseBytes  int
+	sanitizeOutput        bool
 	enableHelmInstall     bool
 	enableHelmUpgrade     bool
 	enableHelmUninstall   bool
@@ -55,6 +62,12 @@ func init() {
 	rootCmd.Flags().BoolVar(&enableUpdate, "enable-update", false, "Enable resource update operations")
 	rootCmd.Flags().BoolVar(&enableDelete, "enable-delete", false, "Enable resource deletion operations")
 	rootCmd.Flags().BoolVar(&enableList, "enable-list", true, "Enable resource list operations")
+	rootCmd.Flags().BoolVar(&enableApply, "enable-apply", false, "Enable server-side apply operations")
+	rootCmd.Flags().BoolVar(&enableExec, "enable-exec", false, "Enable running commands in pod containers")
+	rootCmd.Flags().StringVar(&copyDir, "copy-dir", filepath.Join(os.TempDir(), "mcp-k8s"), "Directory on the server host that large files copied from pods are written to")
+	rootCmd.Flags().BoolVar(&enablePortForward, "enable-port-forward", false, "Enable port-forwards from the server host to pods and services")
+	rootCmd.Flags().IntVar(&listMaxResponseBytes, "list-max-response-bytes", tools.DefaultListMaxResponseBytes, "Maximum size in bytes of a list_resources response before it is truncated to the items that fit (0 disables the limit)")
+	rootCmd.Flags().BoolVar(&sanitizeOutput, "sanitize-output", true, "Drop managedFields and the last-applied-configuration annotation from returned resources by default")
 
 	// Helm operations
 	rootCmd.Flags().BoolVar(&enableHelmInstall, "enable-helm-install", false, "Enable Helm install operations")
@@ -76,6 +89,12 @@ func init() {
 func runServer(cmd *cobra.Command, args []string) {
 	// Create configuration
 	cfg := config.NewConfig(kubeconfigPath, enableCreate, enableUpdate, enableDelete, enableList)
+	cfg.EnableApply = enableApply
+	cfg.EnableExec = enableExec
+	cfg.EnablePortForward = enablePortForward
+	cfg.CopyDir = copyDir
+	cfg.ListMaxResponseBytes = listMaxResponseBytes
+	cfg.SanitizeOutput = sanitizeOutput
 
 	// Set Helm-related configuration
 	// Initialize Helm default configuration
@@ -107,20 +126,36 @@ func runServer(cmd *cobra.Command, args []string) {
 	s := server.NewMCPServer(
 		"Kubernetes MCP Server",
 		version,
+		// Followed logs are pushed to clients as log message notifications
+		server.WithLogging(),
 	)
 
+	outputConfig := tools.OutputConfig{
+		Sanitize:             cfg.SanitizeOutput,
+		ListMaxResponseBytes: cfg.ListMaxResponseBytes,
+	}
+
 	// Add basic tools
 	fmt.Println("Registering basic tools...")
 	s.AddTool(tools.CreateGetAPIResourcesTool(), tools.HandleGetAPIResources(client))
-	s.AddTool(tools.CreateGetResourceTool(), tools.HandleGetResource(client))
+	s.AddTool(tools.CreateGetResourceTool(), tools.HandleGetResource(client, outputConfig))
 	if cfg.EnableList {
-		s.AddTool(tools.CreateListResourcesTool(), tools.HandleListResources(client))
+		s.AddTool(tools.CreateListResourcesTool(), tools.HandleListResources(client, outputConfig))
 	}
 
 	// Add operational tools (always enabled for read operations)
 	fmt.Println("Registering operational tools...")
 	s.AddTool(tools.CreateGetPodLogsTool(), tools.HandleGetPodLogs(client))
+	s.AddTool(tools.CreateGetWorkloadLogsTool(), tools.HandleGetWorkloadLogs(client))
 	s.AddTool(tools.CreateListEventsTool(), tools.HandleListEvents(client))
+	s.AddTool(tools.CreateDescribeResourceTool(), tools.HandleDescribeResource(client))
+	s.AddTool(tools.CreateTopNodesTool(), tools.HandleTopNodes(client))
+	s.AddTool(tools.CreateTopPodsTool(), tools.HandleTopPods(client))
+	s.AddTool(tools.CreateDiffResourceTool(), tools.HandleDiffResource(client))
+	s.AddTool(tools.CreateWatchResourcesTool(), tools.HandleWatchResources(client))
+	s.AddTool(tools.CreateWaitForConditionTool(), tools.HandleWaitForCondition(client))
+	s.AddTool(tools.CreateRolloutStatusTool(), tools.HandleRolloutStatus(client))
+	s.AddTool(tools.CreateRolloutHistoryTool(), tools.HandleRolloutHistory(client))
 
 	// Add write operation tools (if enabled)
 	if cfg.EnableCreate {
@@ -129,8 +164,14 @@ func runServer(cmd *cobra.Command, args []string) {
 	}
 
 	if cfg.EnableUpdate {
-		fmt.Println("Registering resource update tool...")
+		fmt.Println("Registering resource update tools...")
 		s.AddTool(tools.CreateUpdateResourceTool(), tools.HandleUpdateResource(client))
+		s.AddTool(tools.CreatePatchResourceTool(), tools.HandlePatchResource(client))
+		s.AddTool(tools.CreateScaleResourceTool(), tools.HandleScaleResource(client))
+		s.AddTool(tools.CreateRolloutRestartTool(), tools.HandleRolloutRestart(client))
+		s.AddTool(tools.CreateRolloutPauseTool(), tools.HandleRolloutPause(client))
+		s.AddTool(tools.CreateRolloutResumeTool(), tools.HandleRolloutResume(client))
+		s.AddTool(tools.CreateRolloutUndoTool(), tools.HandleRolloutUndo(client))
 	}
 
 	if cfg.EnableDelete {
@@ -138,6 +179,26 @@ func runServer(cmd *cobra.Command, args []string) {
 		s.AddTool(tools.CreateDeleteResourceTool(), tools.HandleDeleteResource(client))
 	}
 
+	if cfg.EnableApply {
+		fmt.Println("Registering resource apply tools...")
+		s.AddTool(tools.CreateApplyResourceTool(), tools.HandleApplyResource(client))
+		s.AddTool(tools.CreateApplyManifestsTool(), tools.HandleApplyManifests(client))
+	}
+
+	if cfg.EnableExec {
+		fmt.Println("Registering pod exec and copy tools...")
+		s.AddTool(tools.CreateExecInPodTool(), tools.HandleExecInPod(client))
+		s.AddTool(tools.CreateCopyFromPodTool(), tools.HandleCopyFromPod(client, cfg.CopyDir))
+		s.AddTool(tools.CreateCopyToPodTool(), tools.HandleCopyToPod(client, cfg.CopyDir))
+	}
+
+	if cfg.EnablePortForward {
+		fmt.Println("Registering port-forward tools...")
+		s.AddTool(tools.CreateStartPortForwardTool(), tools.HandleStartPortForward(client))
+		s.AddTool(tools.CreateListPortForwardsTool(), tools.HandleListPortForwards(client))
+		s.AddTool(tools.CreateStopPortForwardTool(), tools.HandleStopPortForward(client))
+	}
+
 	// Add Helm tools (if enabled)
 	fmt.Println("Registering Helm tools...")
 
@@ -191,6 +252,9 @@ func runServer(cmd *cobra.Command, args []string) {
 	fmt.Printf("Update operations: %v\n", cfg.EnableUpdate)
 	fmt.Printf("Delete operations: %v\n", cfg.EnableDelete)
 	fmt.Printf("List operations: %v\n", cfg.EnableList)
+	fmt.Printf("Apply operations: %v\n", cfg.EnableApply)
+	fmt.Printf("Exec operations: %v\n", cfg.EnableExec)
+	fmt.Printf("Port-forward operations: %v\n", cfg.EnablePortForward)
 
 	fmt.Println("\nHelm operations details:")
 	fmt.Printf("  Helm release list: %v\n", cfg.EnableHelmReleaseList)
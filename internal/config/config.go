@@ -17,6 +17,18 @@ type Config struct {
 	EnableDelete bool
 	// Whether to enable resource list operations
 	EnableList bool
+	// Whether to enable server-side apply operations
+	EnableApply bool
+	// Whether to enable running commands in pod containers
+	EnableExec bool
+	// Directory on the server host that files copied from pods are written to
+	CopyDir string
+	// Whether to enable port-forwards from the server host to pods and services
+	EnablePortForward bool
+	// Maximum size in bytes of a list_resources response, 0 disables the limit
+	ListMaxResponseBytes int
+	// Whether to drop managedFields and last-applied-configuration from returned resources by default
+	SanitizeOutput bool
 	// Whether to enable Helm install operations
 	EnableHelmInstall bool
 	// Whether to enable Helm upgrade operations
@@ -48,6 +60,10 @@ func NewConfig(kubeconfigPath string, enableCreate, enableUpdate, enableDelete,
 
 // Validate validates whether the configuration is valid
 func (c *Config) Validate() error {
+	if c.ListMaxResponseBytes < 0 {
+		return fmt.Errorf("list response size limit must not be negative: %d", c.ListMaxResponseBytes)
+	}
+
 	// Check if kubeconfig is accessible
 	if c.KubeconfigPath != "" {
 		_, err := os.Stat(c.KubeconfigPath)
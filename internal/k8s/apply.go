@@ -0,0 +1,217 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// DefaultFieldManager is the field manager used for server-side apply when none is given
+	DefaultFieldManager = "mcp-k8s"
+)
+
+// conflictManagerPattern extracts the conflicting manager from a field manager conflict message,
+// e.g. `conflict with "kubectl-client-side-apply" using apps/v1: .spec.replicas`
+var conflictManagerPattern = regexp.MustCompile(`conflict with "([^"]*)"`)
+
+// ApplyConflict describes a field owned by another field manager that blocked an apply
+type ApplyConflict struct {
+	Field   string `json:"field"`
+	Manager string `json:"manager,omitempty"`
+	Message string `json:"message"`
+}
+
+// ApplyConflictError is returned when server-side apply is rejected because of field conflicts
+type ApplyConflictError struct {
+	FieldManager string          `json:"fieldManager"`
+	Conflicts    []ApplyConflict `json:"conflicts"`
+	Err          error           `json:"-"`
+}
+
+// Error implements the error interface
+func (e *ApplyConflictError) Error() string {
+	fields := make([]string, 0, len(e.Conflicts))
+	for _, conflict := range e.Conflicts {
+		if conflict.Manager != "" {
+			fields = append(fields, fmt.Sprintf("%s (owned by %s)", conflict.Field, conflict.Manager))
+		} else {
+			fields = append(fields, conflict.Field)
+		}
+	}
+	return fmt.Sprintf("apply conflicts with other field managers on: %s", strings.Join(fields, ", "))
+}
+
+// Unwrap returns the underlying API error
+func (e *ApplyConflictError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyResource creates or updates a resource using server-side apply
+func (c *Client) ApplyResource(ctx context.Context, resourceType ResourceType, namespace, manifest, fieldManager string, force, dryRun bool) (map[string]interface{}, error) {
+	obj, err := parseManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.applyObject(ctx, resourceType, namespace, obj, fieldManager, force, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.UnstructuredContent(), nil
+}
+
+// applyObject applies a parsed object using server-side apply
+func (c *Client) applyObject(ctx context.Context, resourceType ResourceType, namespace string, obj *unstructured.Unstructured, fieldManager string, force, dryRun bool) (*unstructured.Unstructured, error) {
+	if obj.GetName() == "" {
+		return nil, fmt.Errorf("resource manifest must set metadata.name")
+	}
+
+	// Get the resource's GVR and the namespace to apply it in
+	mapping, targetNamespace, err := c.manifestTarget(resourceType, namespace, obj)
+	if err != nil {
+		return nil, err
+	}
+	gvr := mapping.Resource
+
+	if fieldManager == "" {
+		fieldManager = DefaultFieldManager
+	}
+	options := metav1.ApplyOptions{
+		FieldManager: fieldManager,
+		Force:        force,
+		DryRun:       dryRunOption(dryRun),
+	}
+
+	var resource dynamic.ResourceInterface = c.dynamicClient.Resource(gvr)
+	if targetNamespace != "" {
+		resource = c.dynamicClient.Resource(gvr).Namespace(targetNamespace)
+	}
+	result, err := resource.Apply(ctx, obj.GetName(), obj, options)
+	if err != nil {
+		if conflictErr := newApplyConflictError(err, fieldManager); conflictErr != nil {
+			return nil, conflictErr
+		}
+		return nil, fmt.Errorf("failed to apply resource: %w", err)
+	}
+
+	return result, nil
+}
+
+// manifestTarget resolves the REST mapping of a manifest and the namespace it is written to. Create,
+// update, apply and diff share it so that they agree on the kind, version and namespace of an object.
+func (c *Client) manifestTarget(resourceType ResourceType, namespace string, obj *unstructured.Unstructured) (*meta.RESTMapping, string, error) {
+	mapping, err := c.manifestMapping(resourceType, obj)
+	if err != nil {
+		return nil, "", err
+	}
+
+	targetNamespace, err := objectNamespace(mapping, obj, namespace)
+	if err != nil {
+		return nil, "", err
+	}
+	return mapping, targetNamespace, nil
+}
+
+// manifestMapping resolves the REST mapping of a manifest from its own apiVersion and kind, like
+// kubectl does, so that a manifest of a version other than the preferred one is sent to the URL
+// of that version. The resource type defaults the apiVersion when the manifest omits it, and must
+// agree with the manifest otherwise. Server-side apply requires apiVersion and kind in the request
+// body, so missing ones are set from the mapping.
+func (c *Client) manifestMapping(resourceType ResourceType, obj *unstructured.Unstructured) (*meta.RESTMapping, error) {
+	manifestType := resourceType
+	if manifestType.APIVersion == "" {
+		manifestType.APIVersion = obj.GetAPIVersion()
+	}
+	mapping, err := c.findResourceMapping(manifestType)
+	if err != nil && manifestType.APIVersion != resourceType.APIVersion {
+		// Resolve from the parameters alone, so that a resource type naming a different kind is
+		// reported as a mismatch below rather than as missing from the manifest's group/version
+		if paramMapping, paramErr := c.findResourceMapping(resourceType); paramErr == nil {
+			mapping, err = paramMapping, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	gvk := mapping.GroupVersionKind
+	if kind := obj.GetKind(); kind != "" && kind != gvk.Kind {
+		return nil, fmt.Errorf("kind %s in the manifest does not match resource type %s (%s)", kind, resourceType.Name, gvk.Kind)
+	}
+	if apiVersion := obj.GetAPIVersion(); apiVersion != "" && apiVersion != gvk.GroupVersion().String() {
+		return nil, fmt.Errorf("apiVersion %s in the manifest does not match resource type %s (%s)", apiVersion, resourceType.Name, gvk.GroupVersion())
+	}
+
+	if obj.GetAPIVersion() == "" || obj.GetKind() == "" {
+		obj.SetGroupVersionKind(gvk)
+	}
+	return mapping, nil
+}
+
+// objectNamespace returns the namespace an object is applied in, like kubectl apply -n. A
+// namespace-scoped object without metadata.namespace is placed in the given namespace, and an
+// explicit metadata.namespace must agree with it. Cluster-scoped objects never get a namespace.
+func objectNamespace(mapping *meta.RESTMapping, obj *unstructured.Unstructured, namespace string) (string, error) {
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		// The API server rejects a namespace on cluster-scoped objects, kubectl drops it as well
+		obj.SetNamespace("")
+		return "", nil
+	}
+
+	switch objNamespace := obj.GetNamespace(); {
+	case objNamespace == "" && namespace == "":
+		return "", fmt.Errorf("namespace is required for namespace-scoped resource %s %s", mapping.GroupVersionKind.Kind, obj.GetName())
+	case objNamespace == "":
+		obj.SetNamespace(namespace)
+		return namespace, nil
+	case namespace != "" && namespace != objNamespace:
+		return "", fmt.Errorf("the namespace from the manifest (%s) does not match the namespace parameter (%s) for %s %s",
+			objNamespace, namespace, mapping.GroupVersionKind.Kind, obj.GetName())
+	default:
+		return objNamespace, nil
+	}
+}
+
+// newApplyConflictError converts a field manager conflict returned by the API server into an
+// ApplyConflictError, it returns nil for any other error
+func newApplyConflictError(err error, fieldManager string) *ApplyConflictError {
+	var statusErr *apierrors.StatusError
+	if !apierrors.IsConflict(err) || !errors.As(err, &statusErr) {
+		return nil
+	}
+
+	details := statusErr.ErrStatus.Details
+	if details == nil {
+		return nil
+	}
+
+	conflictErr := &ApplyConflictError{FieldManager: fieldManager, Err: err}
+	for _, cause := range details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+
+		conflict := ApplyConflict{
+			Field:   cause.Field,
+			Message: cause.Message,
+		}
+		if matches := conflictManagerPattern.FindStringSubmatch(cause.Message); len(matches) == 2 {
+			conflict.Manager = matches[1]
+		}
+		conflictErr.Conflicts = append(conflictErr.Conflicts, conflict)
+	}
+
+	if len(conflictErr.Conflicts) == 0 {
+		return nil
+	}
+	return conflictErr
+}
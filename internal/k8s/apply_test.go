@@ -0,0 +1,101 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// newFakeApplyVersionClient returns a client that records the resource of every apply request
+func newFakeApplyVersionClient() (*Client, *[]schema.GroupVersionResource) {
+	client := newFakeDiscoveryClient(testAPIResources)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	resources := &[]schema.GroupVersionResource{}
+	dynamicClient.PrependReactor("patch", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		*resources = append(*resources, action.GetResource())
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(action.(clienttesting.PatchAction).GetPatch()); err != nil {
+			return true, nil, err
+		}
+		return true, obj, nil
+	})
+	client.dynamicClient = dynamicClient
+	return client, resources
+}
+
+const hpaV1Manifest = `apiVersion: autoscaling/v1
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+  namespace: default
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: web
+  maxReplicas: 3
+`
+
+func TestApplyResourceUsesManifestVersion(t *testing.T) {
+	client, resources := newFakeApplyVersionClient()
+
+	_, err := client.ApplyResource(context.Background(), ResourceType{Name: "hpa"}, "", hpaV1Manifest, "", false, false)
+	if err != nil {
+		t.Fatalf("ApplyResource returned error: %v", err)
+	}
+
+	want := schema.GroupVersionResource{Group: "autoscaling", Version: "v1", Resource: "horizontalpodautoscalers"}
+	if len(*resources) != 1 || (*resources)[0] != want {
+		t.Errorf("applied to %v, want %v", *resources, want)
+	}
+}
+
+func TestApplyResourceRejectsMismatchedType(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceType ResourceType
+		want         string
+	}{
+		{"kind", ResourceType{Name: "Deployment"}, "kind HorizontalPodAutoscaler in the manifest does not match"},
+		{"apiVersion", ResourceType{Name: "hpa", APIVersion: "autoscaling/v2"}, "apiVersion autoscaling/v1 in the manifest does not match"},
+		{"group", ResourceType{Name: "hpa", Group: "apps"}, "conflicting API groups"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, resources := newFakeApplyVersionClient()
+
+			_, err := client.ApplyResource(context.Background(), tt.resourceType, "", hpaV1Manifest, "", false, false)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("ApplyResource error = %v, want %q", err, tt.want)
+			}
+			if len(*resources) != 0 {
+				t.Errorf("mismatched manifest was applied to %v", *resources)
+			}
+		})
+	}
+}
+
+func TestApplyResourceDefaultsTypeFromParameters(t *testing.T) {
+	client, resources := newFakeApplyVersionClient()
+
+	manifest := "metadata:\n  name: settings\n  namespace: default\ndata:\n  key: value\n"
+	applied, err := client.ApplyResource(context.Background(), ResourceType{Name: "configmaps"}, "", manifest, "", false, false)
+	if err != nil {
+		t.Fatalf("ApplyResource returned error: %v", err)
+	}
+
+	obj := &unstructured.Unstructured{Object: applied}
+	if obj.GetAPIVersion() != "v1" || obj.GetKind() != "ConfigMap" {
+		t.Errorf("applied object is %s %s, want v1 ConfigMap", obj.GetAPIVersion(), obj.GetKind())
+	}
+	if len(*resources) != 1 || (*resources)[0].Resource != "configmaps" {
+		t.Errorf("applied to %v, want configmaps", *resources)
+	}
+}
@@ -4,20 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"log"
 	"path/filepath"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
+	"k8s.io/client-go/util/retry"
 )
 
 const (
@@ -35,6 +36,10 @@ type Client struct {
 	dynamicClient dynamic.Interface
 	// Discovery client
 	discoveryClient *discovery.DiscoveryClient
+	// Cached discovery data and REST mapper shared by all handlers
+	discovery *discoveryCache
+	// Open port-forwards started by StartPortForward
+	portForwards *portForwardRegistry
 	// REST config
 	restConfig *rest.Config
 	// kubeconfig path
@@ -87,6 +92,8 @@ func NewClient(kubeconfigPath string) (*Client, error) {
 		clientset:       clientset,
 		dynamicClient:   dynamicClient,
 		discoveryClient: discoveryClient,
+		discovery:       newDiscoveryCache(discoveryClient, DefaultDiscoveryCacheTTL),
+		portForwards:    newPortForwardRegistry(),
 		restConfig:      config,
 		kubeconfigPath:  kubeconfig,
 	}, nil
@@ -95,12 +102,9 @@ func NewClient(kubeconfigPath string) (*Client, error) {
 // GetAPIResources gets all API resource types in the cluster
 func (c *Client) GetAPIResources(ctx context.Context, includeNamespaceScoped, includeClusterScoped bool) ([]map[string]interface{}, error) {
 	// Get all API Groups and Resources in the cluster
-	resourceLists, err := c.discoveryClient.ServerPreferredResources()
+	resourceLists, err := c.discovery.serverPreferredResources()
 	if err != nil {
-		// Handle partial errors, some resources may not be accessible
-		if !discovery.IsGroupDiscoveryFailedError(err) {
-			return nil, fmt.Errorf("failed to get API resources: %w", err)
-		}
+		return nil, err
 	}
 
 	var resources []map[string]interface{}
@@ -129,6 +133,7 @@ func (c *Client) GetAPIResources(ctx context.Context, includeNamespaceScoped, in
 			resources = append(resources, map[string]interface{}{
 				"name":         resource.Name,
 				"singularName": resource.SingularName,
+				"shortNames":   resource.ShortNames,
 				"namespaced":   resource.Namespaced,
 				"kind":         resource.Kind,
 				"group":        resource.Group,
@@ -142,18 +147,19 @@ func (c *Client) GetAPIResources(ctx context.Context, includeNamespaceScoped, in
 }
 
 // GetResource gets detailed information about a specific resource
-func (c *Client) GetResource(ctx context.Context, kind, name, namespace string) (map[string]interface{}, error) {
+func (c *Client) GetResource(ctx context.Context, resourceType ResourceType, name, namespace string) (map[string]interface{}, error) {
 	// Get the resource's GVR
-	gvr, err := c.findGroupVersionResource(kind)
+	mapping, err := c.findResourceMapping(resourceType)
 	if err != nil {
 		return nil, err
 	}
+	gvr := mapping.Resource
 
 	var obj *unstructured.Unstructured
 	if namespace != "" {
-		obj, err = c.dynamicClient.Resource(*gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		obj, err = c.dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
 	} else {
-		obj, err = c.dynamicClient.Resource(*gvr).Get(ctx, name, metav1.GetOptions{})
+		obj, err = c.dynamicClient.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
 	}
 
 	if err != nil {
@@ -163,15 +169,35 @@ func (c *Client) GetResource(ctx context.Context, kind, name, namespace string)
 	return obj.UnstructuredContent(), nil
 }
 
-// ListResources lists all instances of a resource type
-func (c *Client) ListResources(ctx context.Context, kind, namespace string, labelSelector, fieldSelector string) ([]map[string]interface{}, error) {
+// ResourceList is a page of resources returned by ListResources
+type ResourceList struct {
+	Items []map[string]interface{} `json:"items"`
+	// Continue is the token to pass to the next ListResources call, empty on the last page
+	Continue string `json:"continue,omitempty"`
+	// RemainingItemCount is the number of items after this page, if known
+	RemainingItemCount *int64 `json:"remainingItemCount,omitempty"`
+	// Truncated reports whether the page was shortened to fit the response size limit. The continue
+	// token is dropped then, as only the API server can issue one pointing after the kept items.
+	Truncated bool `json:"truncated,omitempty"`
+	// SuggestedLimit is the number of items that fit the response size limit when the page was
+	// truncated, pass it as the limit to page through the resources with continue tokens
+	SuggestedLimit int `json:"suggestedLimit,omitempty"`
+}
+
+// ListResources lists instances of a resource type. A limit of 0 lists all instances, otherwise
+// at most limit instances are returned together with a continue token for the next page.
+func (c *Client) ListResources(ctx context.Context, resourceType ResourceType, namespace string, labelSelector, fieldSelector string, limit int64, continueToken string) (*ResourceList, error) {
 	// Get the resource's GVR
-	gvr, err := c.findGroupVersionResource(kind)
+	mapping, err := c.findResourceMapping(resourceType)
 	if err != nil {
 		return nil, err
 	}
+	gvr := mapping.Resource
 
-	options := metav1.ListOptions{}
+	options := metav1.ListOptions{
+		Limit:    limit,
+		Continue: continueToken,
+	}
 	if labelSelector != "" {
 		options.LabelSelector = labelSelector
 	}
@@ -181,46 +207,46 @@ func (c *Client) ListResources(ctx context.Context, kind, namespace string, labe
 
 	var list *unstructured.UnstructuredList
 	if namespace != "" {
-		list, err = c.dynamicClient.Resource(*gvr).Namespace(namespace).List(ctx, options)
+		list, err = c.dynamicClient.Resource(gvr).Namespace(namespace).List(ctx, options)
 	} else {
-		list, err = c.dynamicClient.Resource(*gvr).List(ctx, options)
+		list, err = c.dynamicClient.Resource(gvr).List(ctx, options)
 	}
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to list resources: %w", err)
 	}
 
-	var resources []map[string]interface{}
+	resources := make([]map[string]interface{}, 0, len(list.Items))
 	for _, item := range list.Items {
 		resources = append(resources, item.UnstructuredContent())
 	}
 
-	return resources, nil
+	return &ResourceList{
+		Items:              resources,
+		Continue:           list.GetContinue(),
+		RemainingItemCount: list.GetRemainingItemCount(),
+	}, nil
 }
 
 // CreateResource creates a new resource
-func (c *Client) CreateResource(ctx context.Context, kind, namespace string, manifest string) (map[string]interface{}, error) {
-	obj := &unstructured.Unstructured{}
-	if err := json.Unmarshal([]byte(manifest), &obj.Object); err != nil {
-		return nil, fmt.Errorf("failed to parse resource manifest: %w", err)
+func (c *Client) CreateResource(ctx context.Context, resourceType ResourceType, namespace string, manifest string, dryRun bool) (map[string]interface{}, error) {
+	obj, err := parseManifest(manifest)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get the resource's GVR
-	gvr, err := c.findGroupVersionResource(kind)
+	// Get the resource's GVR and the namespace to create it in
+	mapping, targetNamespace, err := c.manifestTarget(resourceType, namespace, obj)
 	if err != nil {
 		return nil, err
 	}
+	gvr := mapping.Resource
 
-	var result *unstructured.Unstructured
-	if namespace != "" || obj.GetNamespace() != "" {
-		targetNamespace := namespace
-		if targetNamespace == "" {
-			targetNamespace = obj.GetNamespace()
-		}
-		result, err = c.dynamicClient.Resource(*gvr).Namespace(targetNamespace).Create(ctx, obj, metav1.CreateOptions{})
-	} else {
-		result, err = c.dynamicClient.Resource(*gvr).Create(ctx, obj, metav1.CreateOptions{})
+	var resource dynamic.ResourceInterface = c.dynamicClient.Resource(gvr)
+	if targetNamespace != "" {
+		resource = c.dynamicClient.Resource(gvr).Namespace(targetNamespace)
 	}
+	result, err := resource.Create(ctx, obj, metav1.CreateOptions{DryRun: dryRunOption(dryRun)})
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create resource: %w", err)
@@ -230,10 +256,10 @@ func (c *Client) CreateResource(ctx context.Context, kind, namespace string, man
 }
 
 // UpdateResource updates an existing resource
-func (c *Client) UpdateResource(ctx context.Context, kind, name, namespace string, manifest string) (map[string]interface{}, error) {
-	obj := &unstructured.Unstructured{}
-	if err := json.Unmarshal([]byte(manifest), &obj.Object); err != nil {
-		return nil, fmt.Errorf("failed to parse resource manifest: %w", err)
+func (c *Client) UpdateResource(ctx context.Context, resourceType ResourceType, name, namespace string, manifest string, dryRun bool) (map[string]interface{}, error) {
+	obj, err := parseManifest(manifest)
+	if err != nil {
+		return nil, err
 	}
 
 	// Check if name matches
@@ -241,18 +267,18 @@ func (c *Client) UpdateResource(ctx context.Context, kind, name, namespace strin
 		return nil, fmt.Errorf("name in resource manifest (%s) does not match requested name (%s)", obj.GetName(), name)
 	}
 
-	// Get the resource's GVR
-	gvr, err := c.findGroupVersionResource(kind)
+	// Get the resource's GVR and the namespace to update it in
+	mapping, targetNamespace, err := c.manifestTarget(resourceType, namespace, obj)
 	if err != nil {
 		return nil, err
 	}
+	gvr := mapping.Resource
 
-	var result *unstructured.Unstructured
-	if namespace != "" {
-		result, err = c.dynamicClient.Resource(*gvr).Namespace(namespace).Update(ctx, obj, metav1.UpdateOptions{})
-	} else {
-		result, err = c.dynamicClient.Resource(*gvr).Update(ctx, obj, metav1.UpdateOptions{})
+	var resource dynamic.ResourceInterface = c.dynamicClient.Resource(gvr)
+	if targetNamespace != "" {
+		resource = c.dynamicClient.Resource(gvr).Namespace(targetNamespace)
 	}
+	result, err := resource.Update(ctx, obj, metav1.UpdateOptions{DryRun: dryRunOption(dryRun)})
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to update resource: %w", err)
@@ -261,92 +287,143 @@ func (c *Client) UpdateResource(ctx context.Context, kind, name, namespace strin
 	return result.UnstructuredContent(), nil
 }
 
-// DeleteResource deletes a resource
-func (c *Client) DeleteResource(ctx context.Context, kind, name, namespace string) error {
+// PatchResource patches an existing resource, optionally through its status or scale subresource
+func (c *Client) PatchResource(ctx context.Context, resourceType ResourceType, name, namespace, patchType, patch, subresource string, dryRun bool) (map[string]interface{}, error) {
+	pt, err := parsePatchType(patchType)
+	if err != nil {
+		return nil, err
+	}
+
+	if !json.Valid([]byte(patch)) {
+		return nil, fmt.Errorf("failed to parse patch: patch must be valid JSON")
+	}
+
+	var subresources []string
+	switch subresource {
+	case "":
+	case "status", "scale":
+		subresources = append(subresources, subresource)
+	default:
+		return nil, fmt.Errorf("unsupported subresource %q, must be one of: status, scale", subresource)
+	}
+
 	// Get the resource's GVR
-	gvr, err := c.findGroupVersionResource(kind)
+	mapping, err := c.findResourceMapping(resourceType)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	gvr := mapping.Resource
 
-	var deleteErr error
+	var result *unstructured.Unstructured
 	if namespace != "" {
-		deleteErr = c.dynamicClient.Resource(*gvr).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		result, err = c.dynamicClient.Resource(gvr).Namespace(namespace).Patch(ctx, name, pt, []byte(patch), metav1.PatchOptions{DryRun: dryRunOption(dryRun)}, subresources...)
 	} else {
-		deleteErr = c.dynamicClient.Resource(*gvr).Delete(ctx, name, metav1.DeleteOptions{})
+		result, err = c.dynamicClient.Resource(gvr).Patch(ctx, name, pt, []byte(patch), metav1.PatchOptions{DryRun: dryRunOption(dryRun)}, subresources...)
 	}
 
-	if deleteErr != nil {
-		return fmt.Errorf("failed to delete resource: %w", deleteErr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch resource: %w", err)
 	}
 
-	return nil
+	return result.UnstructuredContent(), nil
 }
 
-// findGroupVersionResource finds the corresponding GroupVersionResource by Kind
-func (c *Client) findGroupVersionResource(kind string) (*schema.GroupVersionResource, error) {
-	// Get all API Groups and Resources in the cluster
-	resourceLists, err := c.discoveryClient.ServerPreferredResources()
+// ScaleResource sets the replica count of any resource exposing the scale subresource, such as
+// Deployments, StatefulSets, ReplicaSets or custom resources. If currentReplicas is not nil the
+// resource is only scaled when its current replica count matches.
+func (c *Client) ScaleResource(ctx context.Context, resourceType ResourceType, name, namespace string, replicas int64, currentReplicas *int64, dryRun bool) (map[string]interface{}, error) {
+	if replicas < 0 {
+		return nil, fmt.Errorf("invalid replicas value: %d, must not be negative", replicas)
+	}
+
+	// Get the resource's GVR
+	mapping, err := c.findResourceMapping(resourceType)
 	if err != nil {
-		// Handle partial errors, some resources may not be accessible
-		if !discovery.IsGroupDiscoveryFailedError(err) {
-			return nil, fmt.Errorf("failed to get API resources: %w", err)
-		}
+		return nil, err
 	}
+	gvr := mapping.Resource
 
-	// Iterate through all API groups and resources to find the specified Kind
-	for _, resourceList := range resourceLists {
-		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+	var resource dynamic.ResourceInterface = c.dynamicClient.Resource(gvr)
+	if namespace != "" {
+		resource = c.dynamicClient.Resource(gvr).Namespace(namespace)
+	}
+
+	var result *unstructured.Unstructured
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		scale, err := resource.Get(ctx, name, metav1.GetOptions{}, "scale")
 		if err != nil {
-			continue
+			return err
 		}
 
-		for _, resource := range resourceList.APIResources {
-			if resource.Kind == kind {
-				return &schema.GroupVersionResource{
-					Group:    gv.Group,
-					Version:  gv.Version,
-					Resource: resource.Name,
-				}, nil
+		if currentReplicas != nil {
+			current, _, err := unstructured.NestedInt64(scale.Object, "spec", "replicas")
+			if err != nil {
+				return fmt.Errorf("failed to read current replicas: %w", err)
 			}
+			if current != *currentReplicas {
+				return fmt.Errorf("precondition failed: expected %d current replicas, found %d", *currentReplicas, current)
+			}
+		}
+
+		if err := unstructured.SetNestedField(scale.Object, replicas, "spec", "replicas"); err != nil {
+			return fmt.Errorf("failed to set replicas: %w", err)
+		}
+
+		// The update carries the resourceVersion read above, so a concurrent change causes a conflict
+		result, err = resource.Update(ctx, scale, metav1.UpdateOptions{DryRun: dryRunOption(dryRun)}, "scale")
+		return err
+	})
+
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to scale resource: %s/%s not found or its type has no scale subresource: %w", resourceType.Name, name, err)
 		}
+		return nil, fmt.Errorf("failed to scale resource: %w", err)
 	}
 
-	return nil, fmt.Errorf("resource type %s not found", kind)
+	return result.UnstructuredContent(), nil
 }
 
-// GetKubeconfigPath returns the kubeconfig path used by the client
-func (c *Client) GetKubeconfigPath() string {
-	return c.kubeconfigPath
+// parsePatchType converts a patch type name (json, merge or strategic) to its API patch type
+func parsePatchType(patchType string) (types.PatchType, error) {
+	switch patchType {
+	case "json":
+		return types.JSONPatchType, nil
+	case "merge":
+		return types.MergePatchType, nil
+	case "strategic":
+		return types.StrategicMergePatchType, nil
+	default:
+		return "", fmt.Errorf("unsupported patch type %q, must be one of: json, merge, strategic", patchType)
+	}
 }
 
-// GetPodLogs retrieves logs from a specific pod
-func (c *Client) GetPodLogs(ctx context.Context, namespace, podName, container string, tailLines int) (string, error) {
-	opts := &corev1.PodLogOptions{
-		TailLines: int64Ptr(int64(tailLines)),
-	}
-	if container != "" {
-		opts.Container = container
+// DeleteResource deletes a resource
+func (c *Client) DeleteResource(ctx context.Context, resourceType ResourceType, name, namespace string, dryRun bool) error {
+	// Get the resource's GVR
+	mapping, err := c.findResourceMapping(resourceType)
+	if err != nil {
+		return err
 	}
+	gvr := mapping.Resource
 
-	req := c.clientset.CoreV1().Pods(namespace).GetLogs(podName, opts)
-	stream, err := req.Stream(ctx)
-	if err != nil {
-		return "", fmt.Errorf("failed to get pod logs: %w", err)
+	var deleteErr error
+	if namespace != "" {
+		deleteErr = c.dynamicClient.Resource(gvr).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRunOption(dryRun)})
+	} else {
+		deleteErr = c.dynamicClient.Resource(gvr).Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRunOption(dryRun)})
 	}
-	defer func(stream io.ReadCloser) {
-		err := stream.Close()
-		if err != nil {
-			log.Printf("failed to close pod logs stream: %v", err)
-		}
-	}(stream)
 
-	logs, err := io.ReadAll(stream)
-	if err != nil {
-		return "", fmt.Errorf("failed to read logs: %w", err)
+	if deleteErr != nil {
+		return fmt.Errorf("failed to delete resource: %w", deleteErr)
 	}
 
-	return string(logs), nil
+	return nil
+}
+
+// GetKubeconfigPath returns the kubeconfig path used by the client
+func (c *Client) GetKubeconfigPath() string {
+	return c.kubeconfigPath
 }
 
 // ListEvents lists events within a namespace or for a specific resource
@@ -410,6 +487,15 @@ func (c *Client) ListEvents(ctx context.Context, namespace, kind, name, fieldSel
 	return result, nil
 }
 
+// dryRunOption returns the DryRun option value for write requests, a server-side dry run
+// runs admission and validation but does not persist the result
+func dryRunOption(dryRun bool) []string {
+	if dryRun {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
+}
+
 // int64Ptr returns a pointer to an int64
 func int64Ptr(i int64) *int64 {
 	return &i
@@ -0,0 +1,87 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+// newFakeWriteClient returns a client whose dynamic client holds the given ConfigMap
+func newFakeWriteClient(t *testing.T) *Client {
+	t.Helper()
+
+	client := newFakeDiscoveryClient(testAPIResources)
+	client.dynamicClient = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+		testConfigMap("settings", map[string]interface{}{"key": "old"}))
+	return client
+}
+
+func TestUpdateResourceUsesManifestNamespace(t *testing.T) {
+	client := newFakeWriteClient(t)
+
+	manifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  namespace: default\ndata:\n  key: new\n"
+	if _, err := client.UpdateResource(context.Background(), ResourceType{Name: "configmaps"}, "settings", "", manifest, false); err != nil {
+		t.Fatalf("UpdateResource returned error: %v", err)
+	}
+
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	updated, err := client.dynamicClient.Resource(configMaps).Namespace(DefaultNamespace).Get(context.Background(), "settings", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get updated ConfigMap: %v", err)
+	}
+	if value := updated.Object["data"].(map[string]interface{})["key"]; value != "new" {
+		t.Errorf("updated data key = %v, want new", value)
+	}
+}
+
+func TestWriteResourceResolvesManifestType(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		manifest  string
+		want      string
+	}{
+		{
+			name:     "kind mismatch",
+			manifest: "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: settings\n  namespace: default\n",
+			want:     "kind Deployment in the manifest does not match",
+		},
+		{
+			name:     "apiVersion mismatch",
+			manifest: "apiVersion: apps/v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  namespace: default\n",
+			want:     "apiVersion apps/v1 in the manifest does not match",
+		},
+		{
+			name:      "namespace mismatch",
+			namespace: "other",
+			manifest:  "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  namespace: default\n",
+			want:      "does not match the namespace parameter",
+		},
+		{
+			name:     "missing namespace",
+			manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n",
+			want:     "namespace is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeWriteClient(t)
+
+			_, err := client.CreateResource(context.Background(), ResourceType{Name: "configmaps"}, tt.namespace, tt.manifest, false)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("CreateResource error = %v, want %q", err, tt.want)
+			}
+
+			_, err = client.UpdateResource(context.Background(), ResourceType{Name: "configmaps"}, "settings", tt.namespace, tt.manifest, false)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("UpdateResource error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
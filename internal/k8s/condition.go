@@ -0,0 +1,143 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Condition is a state of a resource to wait or watch for, written like the --for flag of kubectl wait:
+//
+//	delete                      the resource is deleted
+//	condition=Available         status.conditions has type Available with status True
+//	condition=Ready=False       status.conditions has type Ready with status False
+//	jsonpath={.status.phase}=Running
+//
+// The condition= prefix may be omitted, e.g. Ready or Available=True.
+type Condition struct {
+	expression string
+	// deleted waits for the resource to be deleted
+	deleted bool
+	// conditionType and conditionStatus match an entry of status.conditions
+	conditionType   string
+	conditionStatus string
+	// jsonPath and value compare the output of a JSONPath expression
+	jsonPath *jsonpath.JSONPath
+	value    string
+}
+
+// ParseCondition parses a condition expression
+func ParseCondition(expression string) (*Condition, error) {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return nil, fmt.Errorf("condition must not be empty")
+	}
+
+	condition := &Condition{expression: expression}
+	lower := strings.ToLower(expression)
+	switch {
+	case lower == "delete" || lower == "deleted":
+		condition.deleted = true
+
+	case strings.HasPrefix(lower, "jsonpath="):
+		template, value, err := splitJSONPathCondition(expression[len("jsonpath="):])
+		if err != nil {
+			return nil, err
+		}
+		parser := jsonpath.New("condition").AllowMissingKeys(true)
+		if err := parser.Parse(template); err != nil {
+			return nil, fmt.Errorf("invalid jsonpath %q: %w", template, err)
+		}
+		condition.jsonPath = parser
+		condition.value = value
+
+	default:
+		spec := expression
+		if strings.HasPrefix(lower, "condition=") {
+			spec = expression[len("condition="):]
+		}
+		conditionType, status, found := strings.Cut(spec, "=")
+		if !found {
+			status = "True"
+		}
+		if conditionType == "" || status == "" {
+			return nil, fmt.Errorf("invalid condition %q, expected e.g. condition=Available=True", expression)
+		}
+		condition.conditionType = conditionType
+		condition.conditionStatus = status
+	}
+
+	return condition, nil
+}
+
+// splitJSONPathCondition splits {.status.phase}=Running into its template and value. The template
+// may be given without braces, e.g. .status.phase=Running.
+func splitJSONPathCondition(spec string) (string, string, error) {
+	var template, value string
+	if strings.HasPrefix(spec, "{") {
+		end := strings.LastIndex(spec, "}")
+		if end < 0 || end+1 >= len(spec) || spec[end+1] != '=' {
+			return "", "", fmt.Errorf("invalid jsonpath condition %q, expected jsonpath={.status.phase}=Running", spec)
+		}
+		template, value = spec[:end+1], spec[end+2:]
+	} else {
+		var found bool
+		template, value, found = strings.Cut(spec, "=")
+		if !found {
+			return "", "", fmt.Errorf("invalid jsonpath condition %q, expected jsonpath={.status.phase}=Running", spec)
+		}
+		template = "{." + strings.TrimPrefix(template, ".") + "}"
+	}
+	// kubectl accepts quoted values
+	value = strings.Trim(value, `"'`)
+	return template, value, nil
+}
+
+// String returns the condition expression
+func (c *Condition) String() string {
+	return c.expression
+}
+
+// WaitsForDeletion reports whether the condition is met by deleting the resource
+func (c *Condition) WaitsForDeletion() bool {
+	return c.deleted
+}
+
+// Matches reports whether an existing resource meets the condition, and the observed value the
+// condition looked at, e.g. the status of a condition or the output of the JSONPath expression
+func (c *Condition) Matches(obj map[string]interface{}) (bool, string, error) {
+	switch {
+	case c.deleted:
+		return false, "exists", nil
+
+	case c.jsonPath != nil:
+		var buf bytes.Buffer
+		if err := c.jsonPath.Execute(&buf, obj); err != nil {
+			return false, "", fmt.Errorf("failed to evaluate condition %s: %w", c.expression, err)
+		}
+		observed := buf.String()
+		return observed == c.value, observed, nil
+
+	default:
+		conditions, _, err := unstructured.NestedSlice(obj, "status", "conditions")
+		if err != nil {
+			return false, "", fmt.Errorf("invalid status.conditions: %w", err)
+		}
+		for _, item := range conditions {
+			entry, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			conditionType, _, _ := unstructured.NestedString(entry, "type")
+			if !strings.EqualFold(conditionType, c.conditionType) {
+				continue
+			}
+			status, _, _ := unstructured.NestedString(entry, "status")
+			return strings.EqualFold(status, c.conditionStatus), status, nil
+		}
+		return false, "", nil
+	}
+}
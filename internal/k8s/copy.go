@@ -0,0 +1,265 @@
+package k8s
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"k8s.io/client-go/util/exec"
+)
+
+const (
+	// DefaultCopyMaxBytes is the default size limit of the data copied from or to a pod
+	DefaultCopyMaxBytes = 10 * 1024 * 1024
+	// DefaultCopyInlineBytes is the size up to which a copied text file is returned inline
+	DefaultCopyInlineBytes = 64 * 1024
+	// copyTimeout is the time limit of a copy from or to a pod
+	copyTimeout = 5 * time.Minute
+)
+
+// CopiedFile describes a file copied from a pod
+type CopiedFile struct {
+	// Path is the path of the file relative to the copied path
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	// Content is the file content, set for small text files
+	Content string `json:"content,omitempty"`
+	// LocalPath is where the file was written on the server host, set for files not returned inline
+	LocalPath string `json:"localPath,omitempty"`
+}
+
+// CopyFromPodResult is the result of CopyFromPod
+type CopyFromPodResult struct {
+	Pod       string       `json:"pod"`
+	Namespace string       `json:"namespace"`
+	Container string       `json:"container"`
+	Path      string       `json:"path"`
+	Files     []CopiedFile `json:"files"`
+	// LocalDir is the directory the files not returned inline were written to
+	LocalDir string `json:"localDir,omitempty"`
+}
+
+// CopyToPodResult is the result of CopyToPod
+type CopyToPodResult struct {
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+	Container string `json:"container"`
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+}
+
+// sizeLimitedWriter buffers up to max bytes and cancels the copy once more are written, so an
+// oversized copy stops early instead of running into its timeout
+type sizeLimitedWriter struct {
+	buf      bytes.Buffer
+	max      int64
+	cancel   context.CancelFunc
+	exceeded atomic.Bool
+}
+
+// Write implements io.Writer
+func (w *sizeLimitedWriter) Write(p []byte) (int, error) {
+	if w.exceeded.Load() {
+		return len(p), nil
+	}
+	if int64(w.buf.Len()+len(p)) > w.max {
+		w.exceeded.Store(true)
+		w.cancel()
+		return len(p), nil
+	}
+	return w.buf.Write(p)
+}
+
+// CopyFromPod copies a file or directory out of a pod container with tar over exec, as kubectl cp
+// does, so the container must have a tar binary. A single text file of at most inlineBytes is
+// returned inline, everything else is written below localDir on the server host. The copy fails if
+// the tar stream exceeds maxBytes.
+func (c *Client) CopyFromPod(ctx context.Context, namespace, podName, container, remotePath, localDir string, maxBytes, inlineBytes int64) (*CopyFromPodResult, error) {
+	remotePath = path.Clean(remotePath)
+	if !path.IsAbs(remotePath) || remotePath == "/" {
+		return nil, fmt.Errorf("path must be an absolute path below /, got %q", remotePath)
+	}
+
+	container, err := c.resolveContainer(ctx, namespace, podName, container)
+	if err != nil {
+		return nil, err
+	}
+
+	copyCtx, cancel := context.WithTimeout(ctx, copyTimeout)
+	defer cancel()
+
+	stdout := &sizeLimitedWriter{max: maxBytes, cancel: cancel}
+	stderr := &limitedBuffer{max: DefaultExecMaxOutputBytes}
+	command := []string{"tar", "cf", "-", "-C", path.Dir(remotePath), path.Base(remotePath)}
+	err = c.streamExec(copyCtx, namespace, podName, container, command, nil, stdout, stderr)
+	if stdout.exceeded.Load() {
+		return nil, fmt.Errorf("the copied data exceeds the size limit of %d bytes", maxBytes)
+	}
+	if err != nil {
+		return nil, copyError(err, stderr)
+	}
+
+	result := &CopyFromPodResult{
+		Pod:       podName,
+		Namespace: namespace,
+		Container: container,
+		Path:      remotePath,
+		Files:     []CopiedFile{},
+	}
+
+	type tarFile struct {
+		name string
+		data []byte
+	}
+	var files []tarFile
+	reader := tar.NewReader(&stdout.buf)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar stream: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar stream: %w", err)
+		}
+		files = append(files, tarFile{name: header.Name, data: data})
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no regular files found at %s", remotePath)
+	}
+
+	// A single small text file is returned inline
+	if len(files) == 1 && int64(len(files[0].data)) <= inlineBytes && isText(files[0].data) {
+		result.Files = append(result.Files, CopiedFile{
+			Path:    files[0].name,
+			Size:    int64(len(files[0].data)),
+			Content: string(files[0].data),
+		})
+		return result, nil
+	}
+
+	if localDir == "" {
+		return nil, fmt.Errorf("the copied data is too large or not text, and no local copy directory is configured")
+	}
+	if err := os.MkdirAll(localDir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	// A unique directory per copy, so concurrent copies from the same pod do not overwrite each other
+	targetDir, err := os.MkdirTemp(localDir, namespace+"-"+podName+"-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	for _, file := range files {
+		localPath, err := localCopyPath(targetDir, file.name)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(localPath), 0o750); err != nil {
+			return nil, fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(localPath, file.data, 0o640); err != nil {
+			return nil, fmt.Errorf("failed to write file: %w", err)
+		}
+		result.Files = append(result.Files, CopiedFile{
+			Path:      file.name,
+			Size:      int64(len(file.data)),
+			LocalPath: localPath,
+		})
+	}
+	result.LocalDir = targetDir
+
+	return result, nil
+}
+
+// CopyToPod writes data to a file in a pod container with tar over exec, as kubectl cp does, so the
+// container must have a tar binary. Missing parent directories are created.
+func (c *Client) CopyToPod(ctx context.Context, namespace, podName, container, remotePath string, data []byte, maxBytes int64) (*CopyToPodResult, error) {
+	remotePath = path.Clean(remotePath)
+	if !path.IsAbs(remotePath) || remotePath == "/" {
+		return nil, fmt.Errorf("path must be an absolute file path, got %q", remotePath)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("file size %d exceeds the copy size limit of %d bytes", len(data), maxBytes)
+	}
+
+	container, err := c.resolveContainer(ctx, namespace, podName, container)
+	if err != nil {
+		return nil, err
+	}
+
+	var archive bytes.Buffer
+	writer := tar.NewWriter(&archive)
+	if err := writer.WriteHeader(&tar.Header{
+		Name:    strings.TrimPrefix(remotePath, "/"),
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to build tar stream: %w", err)
+	}
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to build tar stream: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build tar stream: %w", err)
+	}
+
+	copyCtx, cancel := context.WithTimeout(ctx, copyTimeout)
+	defer cancel()
+
+	stdout := &limitedBuffer{max: DefaultExecMaxOutputBytes}
+	stderr := &limitedBuffer{max: DefaultExecMaxOutputBytes}
+	command := []string{"tar", "xmf", "-", "-C", "/"}
+	if err := c.streamExec(copyCtx, namespace, podName, container, command, &archive, stdout, stderr); err != nil {
+		return nil, copyError(err, stderr)
+	}
+
+	return &CopyToPodResult{
+		Pod:       podName,
+		Namespace: namespace,
+		Container: container,
+		Path:      remotePath,
+		Size:      int64(len(data)),
+	}, nil
+}
+
+// copyError describes a failed tar command, including its error output
+func copyError(err error, stderr *limitedBuffer) error {
+	var exitErr exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("tar failed with exit code %d: %s", exitErr.ExitStatus(), strings.TrimSpace(stderr.buf.String()))
+	}
+	return err
+}
+
+// localCopyPath returns where a file from a tar stream is written below dir, rejecting names
+// that would escape it
+func localCopyPath(dir, name string) (string, error) {
+	localPath := filepath.Join(dir, filepath.FromSlash(name))
+	relative, err := filepath.Rel(dir, localPath)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("refusing to write %q outside of %s", name, dir)
+	}
+	return localPath, nil
+}
+
+// isText reports whether data looks like UTF-8 text
+func isText(data []byte) bool {
+	return utf8.Valid(data) && !bytes.ContainsRune(data, 0)
+}
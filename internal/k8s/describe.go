@@ -0,0 +1,554 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DefaultDescribeEvents is the number of most recent events included in a description
+const DefaultDescribeEvents = 20
+
+// ResourceDescription is a kubectl describe like summary of a resource and its related state
+type ResourceDescription struct {
+	Kind        string            `json:"kind"`
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Created     time.Time         `json:"created"`
+
+	Pod        *PodDescription        `json:"pod,omitempty"`
+	Deployment *DeploymentDescription `json:"deployment,omitempty"`
+	Service    *ServiceDescription    `json:"service,omitempty"`
+	// Conditions are the status conditions of kinds without a dedicated description
+	Conditions []interface{} `json:"conditions,omitempty"`
+
+	Events []DescribedEvent `json:"events"`
+}
+
+// PodDescription summarizes the state of a pod
+type PodDescription struct {
+	Phase      string                 `json:"phase"`
+	Node       string                 `json:"node,omitempty"`
+	PodIP      string                 `json:"podIP,omitempty"`
+	QOSClass   string                 `json:"qosClass,omitempty"`
+	Reason     string                 `json:"reason,omitempty"`
+	Message    string                 `json:"message,omitempty"`
+	Conditions []PodCondition         `json:"conditions,omitempty"`
+	Containers []ContainerDescription `json:"containers"`
+	Volumes    []VolumeDescription    `json:"volumes,omitempty"`
+}
+
+// PodCondition is a status condition of a pod
+type PodCondition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+}
+
+// ContainerDescription summarizes the state of a container of a pod
+type ContainerDescription struct {
+	Name         string `json:"name"`
+	Image        string `json:"image"`
+	Init         bool   `json:"init,omitempty"`
+	Ready        bool   `json:"ready"`
+	RestartCount int32  `json:"restartCount"`
+	// State is waiting, running or terminated
+	State           string                  `json:"state"`
+	Reason          string                  `json:"reason,omitempty"`
+	Message         string                  `json:"message,omitempty"`
+	StartedAt       *time.Time              `json:"startedAt,omitempty"`
+	ExitCode        *int32                  `json:"exitCode,omitempty"`
+	LastTermination *TerminationDescription `json:"lastTermination,omitempty"`
+	Requests        map[string]string       `json:"requests,omitempty"`
+	Limits          map[string]string       `json:"limits,omitempty"`
+}
+
+// TerminationDescription describes how a container instance terminated
+type TerminationDescription struct {
+	Reason     string    `json:"reason,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	ExitCode   int32     `json:"exitCode"`
+	Signal     int32     `json:"signal,omitempty"`
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+// VolumeDescription describes a volume of a pod
+type VolumeDescription struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Source is the name of the backing object, e.g. the ConfigMap, Secret or PersistentVolumeClaim
+	Source string `json:"source,omitempty"`
+}
+
+// DeploymentDescription summarizes a deployment with the ReplicaSets and pods it owns
+type DeploymentDescription struct {
+	Strategy    string              `json:"strategy"`
+	Selector    string              `json:"selector"`
+	Rollout     *RolloutStatus      `json:"rollout"`
+	ReplicaSets []ReplicaSetSummary `json:"replicaSets"`
+	Pods        []PodSummary        `json:"pods"`
+	Images      []string            `json:"images"`
+}
+
+// ReplicaSetSummary summarizes a ReplicaSet owned by a deployment
+type ReplicaSetSummary struct {
+	// UID selects the events of the ReplicaSet
+	UID      types.UID `json:"-"`
+	Name     string    `json:"name"`
+	Revision int64     `json:"revision"`
+	Desired  int32     `json:"desired"`
+	Ready    int32     `json:"ready"`
+	Images   []string  `json:"images"`
+}
+
+// PodSummary summarizes a pod in a listing
+type PodSummary struct {
+	Name string `json:"name"`
+	// Owner is the name of the ReplicaSet owning the pod
+	Owner    string `json:"owner,omitempty"`
+	Phase    string `json:"phase"`
+	Ready    string `json:"ready"`
+	Restarts int32  `json:"restarts"`
+	Node     string `json:"node,omitempty"`
+}
+
+// ServiceDescription summarizes a service with its endpoints
+type ServiceDescription struct {
+	Type         string            `json:"type"`
+	ClusterIP    string            `json:"clusterIP,omitempty"`
+	ExternalIPs  []string          `json:"externalIPs,omitempty"`
+	LoadBalancer []string          `json:"loadBalancer,omitempty"`
+	Selector     map[string]string `json:"selector,omitempty"`
+	Ports        []string          `json:"ports"`
+	Endpoints    []EndpointSummary `json:"endpoints"`
+}
+
+// EndpointSummary describes an endpoint of a service
+type EndpointSummary struct {
+	Address string   `json:"address"`
+	Ports   []string `json:"ports,omitempty"`
+	Ready   bool     `json:"ready"`
+	Pod     string   `json:"pod,omitempty"`
+	Node    string   `json:"node,omitempty"`
+}
+
+// DescribedEvent is a compact event related to a described resource
+type DescribedEvent struct {
+	Type     string    `json:"type"`
+	Reason   string    `json:"reason"`
+	Object   string    `json:"object"`
+	Message  string    `json:"message"`
+	Count    int32     `json:"count,omitempty"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// describedObject identifies an object whose events are included in a description
+type describedObject struct {
+	kind string
+	name string
+	uid  types.UID
+}
+
+// DescribeResource returns a kubectl describe like summary of a resource. Pods include container
+// states, restarts, last terminations, conditions and volumes, Deployments their ReplicaSets and
+// pods, and Services their endpoints. All kinds include their most recent events.
+func (c *Client) DescribeResource(ctx context.Context, resourceType ResourceType, name, namespace string) (*ResourceDescription, error) {
+	mapping, err := c.findResourceMapping(resourceType)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		namespace = ""
+	}
+
+	// Pods, Deployments and Services are converted to their typed form instead of fetched again
+	obj, err := c.GetResource(ctx, resourceType, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+	resource := &unstructured.Unstructured{Object: obj}
+
+	description := &ResourceDescription{
+		Kind:        mapping.GroupVersionKind.Kind,
+		Name:        resource.GetName(),
+		Namespace:   resource.GetNamespace(),
+		Labels:      resource.GetLabels(),
+		Annotations: resource.GetAnnotations(),
+		Created:     resource.GetCreationTimestamp().Time,
+	}
+	// The last applied configuration duplicates the whole object
+	delete(description.Annotations, "kubectl.kubernetes.io/last-applied-configuration")
+
+	// involvedObjects are the objects whose events are included. Events are matched by UID as
+	// well, so that events of a deleted object with the same name are left out.
+	involvedObjects := []describedObject{{kind: description.Kind, name: name, uid: resource.GetUID()}}
+
+	switch mapping.GroupVersionKind.GroupKind() {
+	case corev1.SchemeGroupVersion.WithKind("Pod").GroupKind():
+		pod := &corev1.Pod{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, pod); err != nil {
+			return nil, fmt.Errorf("failed to convert pod: %w", err)
+		}
+		description.Pod = describePod(pod)
+
+	case appsv1.SchemeGroupVersion.WithKind(KindDeployment).GroupKind():
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, deployment); err != nil {
+			return nil, fmt.Errorf("failed to convert deployment: %w", err)
+		}
+		description.Deployment, err = c.describeDeployment(ctx, deployment)
+		if err != nil {
+			return nil, err
+		}
+		// ReplicaSet events report pods being created and deleted during rollouts
+		for _, replicaSet := range description.Deployment.ReplicaSets {
+			involvedObjects = append(involvedObjects, describedObject{kind: "ReplicaSet", name: replicaSet.Name, uid: replicaSet.UID})
+		}
+
+	case corev1.SchemeGroupVersion.WithKind("Service").GroupKind():
+		service := &corev1.Service{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, service); err != nil {
+			return nil, fmt.Errorf("failed to convert service: %w", err)
+		}
+		description.Service, err = c.describeService(ctx, service)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		conditions, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+		description.Conditions = conditions
+	}
+
+	description.Events = []DescribedEvent{}
+	for _, involved := range involvedObjects {
+		var fieldSelector string
+		if involved.uid != "" {
+			fieldSelector = "involvedObject.uid=" + string(involved.uid)
+		}
+		events, err := c.ListEvents(ctx, namespace, involved.kind, involved.name, fieldSelector)
+		if err != nil {
+			return nil, err
+		}
+		description.Events = append(description.Events, describeEvents(events)...)
+	}
+	description.Events = recentEvents(description.Events, DefaultDescribeEvents)
+
+	return description, nil
+}
+
+// describePod summarizes the state of a pod
+func describePod(pod *corev1.Pod) *PodDescription {
+	description := &PodDescription{
+		Phase:      string(pod.Status.Phase),
+		Node:       pod.Spec.NodeName,
+		PodIP:      pod.Status.PodIP,
+		QOSClass:   string(pod.Status.QOSClass),
+		Reason:     pod.Status.Reason,
+		Message:    pod.Status.Message,
+		Containers: []ContainerDescription{},
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		description.Conditions = append(description.Conditions, PodCondition{
+			Type:               string(condition.Type),
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: condition.LastTransitionTime.Time,
+		})
+	}
+
+	statuses := map[string]corev1.ContainerStatus{}
+	for _, status := range append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+		statuses[status.Name] = status
+	}
+	for _, container := range pod.Spec.InitContainers {
+		description.Containers = append(description.Containers, describeContainer(container, statuses[container.Name], true))
+	}
+	for _, container := range pod.Spec.Containers {
+		description.Containers = append(description.Containers, describeContainer(container, statuses[container.Name], false))
+	}
+
+	for _, volume := range pod.Spec.Volumes {
+		description.Volumes = append(description.Volumes, describeVolume(volume))
+	}
+
+	return description
+}
+
+// describeContainer summarizes the state of a container
+func describeContainer(container corev1.Container, status corev1.ContainerStatus, init bool) ContainerDescription {
+	description := ContainerDescription{
+		Name:         container.Name,
+		Image:        container.Image,
+		Init:         init,
+		Ready:        status.Ready,
+		RestartCount: status.RestartCount,
+		State:        "waiting",
+		Requests:     resourceListStrings(container.Resources.Requests),
+		Limits:       resourceListStrings(container.Resources.Limits),
+	}
+
+	switch {
+	case status.State.Running != nil:
+		description.State = "running"
+		startedAt := status.State.Running.StartedAt.Time
+		description.StartedAt = &startedAt
+	case status.State.Terminated != nil:
+		terminated := status.State.Terminated
+		description.State = "terminated"
+		description.Reason = terminated.Reason
+		description.Message = terminated.Message
+		exitCode := terminated.ExitCode
+		description.ExitCode = &exitCode
+	case status.State.Waiting != nil:
+		description.Reason = status.State.Waiting.Reason
+		description.Message = status.State.Waiting.Message
+	}
+
+	if terminated := status.LastTerminationState.Terminated; terminated != nil {
+		description.LastTermination = &TerminationDescription{
+			Reason:     terminated.Reason,
+			Message:    terminated.Message,
+			ExitCode:   terminated.ExitCode,
+			Signal:     terminated.Signal,
+			FinishedAt: terminated.FinishedAt.Time,
+		}
+	}
+
+	return description
+}
+
+// describeVolume returns the type and source of a pod volume
+func describeVolume(volume corev1.Volume) VolumeDescription {
+	description := VolumeDescription{Name: volume.Name, Type: "Other"}
+	source := volume.VolumeSource
+	switch {
+	case source.ConfigMap != nil:
+		description.Type, description.Source = "ConfigMap", source.ConfigMap.Name
+	case source.Secret != nil:
+		description.Type, description.Source = "Secret", source.Secret.SecretName
+	case source.PersistentVolumeClaim != nil:
+		description.Type, description.Source = "PersistentVolumeClaim", source.PersistentVolumeClaim.ClaimName
+	case source.EmptyDir != nil:
+		description.Type = "EmptyDir"
+	case source.HostPath != nil:
+		description.Type, description.Source = "HostPath", source.HostPath.Path
+	case source.Projected != nil:
+		description.Type = "Projected"
+	case source.DownwardAPI != nil:
+		description.Type = "DownwardAPI"
+	case source.CSI != nil:
+		description.Type, description.Source = "CSI", source.CSI.Driver
+	case source.Ephemeral != nil:
+		description.Type = "Ephemeral"
+	case source.NFS != nil:
+		description.Type, description.Source = "NFS", source.NFS.Server+":"+source.NFS.Path
+	}
+	return description
+}
+
+// describeDeployment summarizes a deployment with the ReplicaSets and pods it owns
+func (c *Client) describeDeployment(ctx context.Context, deployment *appsv1.Deployment) (*DeploymentDescription, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deployment selector: %w", err)
+	}
+
+	description := &DeploymentDescription{
+		Strategy:    string(deployment.Spec.Strategy.Type),
+		Selector:    selector.String(),
+		Rollout:     deploymentRolloutStatus(deployment),
+		ReplicaSets: []ReplicaSetSummary{},
+		Pods:        []PodSummary{},
+		Images:      podTemplateImages(&deployment.Spec.Template),
+	}
+
+	replicaSets, err := c.deploymentReplicaSets(ctx, deployment)
+	if err != nil {
+		return nil, err
+	}
+	owned := map[string]bool{}
+	// Newest revisions first, like kubectl describe
+	for i := len(replicaSets) - 1; i >= 0; i-- {
+		replicaSet := &replicaSets[i]
+		owned[string(replicaSet.UID)] = true
+		description.ReplicaSets = append(description.ReplicaSets, ReplicaSetSummary{
+			UID:      replicaSet.UID,
+			Name:     replicaSet.Name,
+			Revision: replicaSetRevision(replicaSet),
+			Desired:  int32Value(replicaSet.Spec.Replicas, 1),
+			Ready:    replicaSet.Status.ReadyReplicas,
+			Images:   podTemplateImages(&replicaSet.Spec.Template),
+		})
+	}
+
+	pods, err := c.clientset.CoreV1().Pods(deployment.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		owner := metav1.GetControllerOf(pod)
+		if owner == nil || !owned[string(owner.UID)] {
+			continue
+		}
+		summary := summarizePod(pod)
+		summary.Owner = owner.Name
+		description.Pods = append(description.Pods, summary)
+	}
+
+	return description, nil
+}
+
+// summarizePod returns the kubectl get like summary of a pod
+func summarizePod(pod *corev1.Pod) PodSummary {
+	ready := 0
+	var restarts int32
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Ready {
+			ready++
+		}
+		restarts += status.RestartCount
+	}
+	return PodSummary{
+		Name:     pod.Name,
+		Phase:    string(pod.Status.Phase),
+		Ready:    fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers)),
+		Restarts: restarts,
+		Node:     pod.Spec.NodeName,
+	}
+}
+
+// describeService summarizes a service with its endpoints from its EndpointSlices
+func (c *Client) describeService(ctx context.Context, service *corev1.Service) (*ServiceDescription, error) {
+	description := &ServiceDescription{
+		Type:        string(service.Spec.Type),
+		ClusterIP:   service.Spec.ClusterIP,
+		ExternalIPs: service.Spec.ExternalIPs,
+		Selector:    service.Spec.Selector,
+		Ports:       []string{},
+		Endpoints:   []EndpointSummary{},
+	}
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			description.LoadBalancer = append(description.LoadBalancer, ingress.IP)
+		} else if ingress.Hostname != "" {
+			description.LoadBalancer = append(description.LoadBalancer, ingress.Hostname)
+		}
+	}
+	for _, port := range service.Spec.Ports {
+		text := fmt.Sprintf("%d/%s -> %s", port.Port, port.Protocol, port.TargetPort.String())
+		if port.Name != "" {
+			text = port.Name + " " + text
+		}
+		if port.NodePort != 0 {
+			text += fmt.Sprintf(" (nodePort %d)", port.NodePort)
+		}
+		description.Ports = append(description.Ports, text)
+	}
+
+	slices, err := c.clientset.DiscoveryV1().EndpointSlices(service.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + service.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpointslices: %w", err)
+	}
+	for _, slice := range slices.Items {
+		var ports []string
+		for _, port := range slice.Ports {
+			if port.Port == nil {
+				continue
+			}
+			text := fmt.Sprintf("%d", *port.Port)
+			if port.Name != nil && *port.Name != "" {
+				text = *port.Name + ":" + text
+			}
+			ports = append(ports, text)
+		}
+		for _, endpoint := range slice.Endpoints {
+			summary := EndpointSummary{
+				Ports: ports,
+				// A nil ready condition means ready
+				Ready: endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready,
+			}
+			if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
+				summary.Pod = endpoint.TargetRef.Name
+			}
+			if endpoint.NodeName != nil {
+				summary.Node = *endpoint.NodeName
+			}
+			for _, address := range endpoint.Addresses {
+				summary.Address = address
+				description.Endpoints = append(description.Endpoints, summary)
+			}
+		}
+	}
+
+	return description, nil
+}
+
+// describeEvents converts events returned by ListEvents to compact events
+func describeEvents(events []map[string]interface{}) []DescribedEvent {
+	described := make([]DescribedEvent, 0, len(events))
+	for _, event := range events {
+		item := DescribedEvent{}
+		item.Type, _ = event["type"].(string)
+		item.Reason, _ = event["reason"].(string)
+		item.Message, _ = event["message"].(string)
+		item.Count, _ = event["count"].(int32)
+		if involved, ok := event["involvedObject"].(map[string]interface{}); ok {
+			kind, _ := involved["kind"].(string)
+			name, _ := involved["name"].(string)
+			item.Object = kind + "/" + name
+		}
+		// Events recorded through the events.k8s.io API leave the legacy timestamps empty
+		if lastTimestamp, ok := event["lastTimestamp"].(metav1.Time); ok && !lastTimestamp.IsZero() {
+			item.LastSeen = lastTimestamp.Time
+		} else if metadata, ok := event["metadata"].(map[string]interface{}); ok {
+			if created, ok := metadata["creationTimestamp"].(metav1.Time); ok {
+				item.LastSeen = created.Time
+			}
+		}
+		described = append(described, item)
+	}
+	return described
+}
+
+// recentEvents sorts events from oldest to newest and keeps the last count of them
+func recentEvents(events []DescribedEvent, count int) []DescribedEvent {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastSeen.Before(events[j].LastSeen)
+	})
+	if len(events) > count {
+		events = events[len(events)-count:]
+	}
+	return events
+}
+
+// resourceListStrings formats a resource list such as container requests
+func resourceListStrings(resources corev1.ResourceList) map[string]string {
+	if len(resources) == 0 {
+		return nil
+	}
+	formatted := make(map[string]string, len(resources))
+	for name, quantity := range resources {
+		formatted[string(name)] = quantity.String()
+	}
+	return formatted
+}
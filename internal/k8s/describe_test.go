@@ -0,0 +1,97 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// testEvent returns an event in the form ListEvents returns it
+func testEvent(reason string, lastTimestamp, created time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":              reason,
+			"creationTimestamp": metav1.NewTime(created),
+		},
+		"involvedObject": map[string]interface{}{
+			"kind": "Pod",
+			"name": "web-0",
+		},
+		"reason":        reason,
+		"message":       reason + " message",
+		"type":          "Normal",
+		"count":         int32(2),
+		"lastTimestamp": metav1.NewTime(lastTimestamp),
+	}
+}
+
+func TestDescribeEvents(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	got := describeEvents([]map[string]interface{}{
+		testEvent("Pulled", base.Add(time.Minute), base),
+		// Events recorded through the events.k8s.io API have no lastTimestamp
+		testEvent("Scheduled", time.Time{}, base.Add(2*time.Minute)),
+	})
+
+	want := []DescribedEvent{
+		{Type: "Normal", Reason: "Pulled", Object: "Pod/web-0", Message: "Pulled message", Count: 2, LastSeen: base.Add(time.Minute)},
+		{Type: "Normal", Reason: "Scheduled", Object: "Pod/web-0", Message: "Scheduled message", Count: 2, LastSeen: base.Add(2 * time.Minute)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("describeEvents = %+v, want %+v", got, want)
+	}
+}
+
+func TestRecentEvents(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	event := func(reason string, minutes int) DescribedEvent {
+		return DescribedEvent{Reason: reason, LastSeen: base.Add(time.Duration(minutes) * time.Minute)}
+	}
+
+	tests := []struct {
+		name   string
+		events []DescribedEvent
+		count  int
+		want   []string
+	}{
+		{
+			name:   "empty",
+			events: []DescribedEvent{},
+			count:  3,
+			want:   []string{},
+		},
+		{
+			name:   "sorted oldest first",
+			events: []DescribedEvent{event("c", 3), event("a", 1), event("b", 2)},
+			count:  3,
+			want:   []string{"a", "b", "c"},
+		},
+		{
+			name:   "keeps the newest",
+			events: []DescribedEvent{event("d", 4), event("a", 1), event("c", 3), event("b", 2)},
+			count:  2,
+			want:   []string{"c", "d"},
+		},
+		{
+			name:   "stable for equal times",
+			events: []DescribedEvent{event("pod", 1), event("replicaset", 1), event("earlier", 0)},
+			count:  3,
+			want:   []string{"earlier", "pod", "replicaset"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, event := range recentEvents(tt.events, tt.count) {
+				got = append(got, event.Reason)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("recentEvents = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
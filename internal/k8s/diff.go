@@ -0,0 +1,218 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DiffFormatUnified renders a diff as a unified diff of the YAML representations
+	DiffFormatUnified = "unified"
+	// DiffFormatStructured renders a diff as a list of changed field paths
+	DiffFormatStructured = "structured"
+)
+
+// diffNoiseFields lists fields that change on every write and are dropped before diffing
+var diffNoiseFields = [][]string{
+	{"metadata", "managedFields"},
+	{"metadata", "resourceVersion"},
+	{"metadata", "generation"},
+	{"metadata", "uid"},
+	{"metadata", "creationTimestamp"},
+	{"status"},
+}
+
+// FieldChange describes a single changed field between the live and the merged object
+type FieldChange struct {
+	Path      string      `json:"path"`
+	Operation string      `json:"op"`
+	Old       interface{} `json:"old,omitempty"`
+	New       interface{} `json:"new,omitempty"`
+}
+
+// ResourceDiff describes what applying a manifest would change on the live object
+type ResourceDiff struct {
+	Kind      string        `json:"kind"`
+	Namespace string        `json:"namespace,omitempty"`
+	Name      string        `json:"name"`
+	Exists    bool          `json:"exists"`
+	Changed   bool          `json:"changed"`
+	Diff      string        `json:"diff,omitempty"`
+	Changes   []FieldChange `json:"changes,omitempty"`
+}
+
+// DiffResource compares a live resource with the result of applying a manifest to it. The merged
+// object is computed by the API server with a server-side dry-run apply, so defaulting and admission
+// webhooks are taken into account. Format is either unified or structured.
+func (c *Client) DiffResource(ctx context.Context, resourceType ResourceType, namespace, manifest, fieldManager, format string, force bool) (*ResourceDiff, error) {
+	if format == "" {
+		format = DiffFormatUnified
+	}
+	if format != DiffFormatUnified && format != DiffFormatStructured {
+		return nil, fmt.Errorf("unsupported diff format %q, must be one of: %s, %s", format, DiffFormatUnified, DiffFormatStructured)
+	}
+
+	obj, err := parseManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	if obj.GetName() == "" {
+		return nil, fmt.Errorf("resource manifest must set metadata.name")
+	}
+
+	// Resolve the mapping and namespace like the apply does, so the live object is read from the
+	// same group/version and namespace the manifest is applied to
+	mapping, targetNamespace, err := c.manifestTarget(resourceType, namespace, obj)
+	if err != nil {
+		return nil, err
+	}
+	manifestType := ResourceType{
+		Name:       mapping.Resource.Resource,
+		APIVersion: mapping.GroupVersionKind.GroupVersion().String(),
+	}
+
+	diff := &ResourceDiff{
+		Kind:      resourceType.Name,
+		Namespace: targetNamespace,
+		Name:      obj.GetName(),
+		Exists:    true,
+	}
+
+	live, err := c.GetResource(ctx, manifestType, obj.GetName(), targetNamespace)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		diff.Exists = false
+		live = map[string]interface{}{}
+	}
+
+	merged, err := c.applyObject(ctx, manifestType, targetNamespace, obj, fieldManager, force, true)
+	if err != nil {
+		return nil, err
+	}
+
+	before := stripDiffNoise(live)
+	after := stripDiffNoise(merged.UnstructuredContent())
+
+	if format == DiffFormatStructured {
+		diff.Changes = diffFields("", before, after)
+		diff.Changed = len(diff.Changes) > 0
+		return diff, nil
+	}
+
+	diff.Diff, err = unifiedDiff(before, after, "live", "merged")
+	if err != nil {
+		return nil, err
+	}
+	diff.Changed = diff.Diff != ""
+
+	return diff, nil
+}
+
+// stripDiffNoise returns a copy of an object without fields that change on every write
+func stripDiffNoise(obj map[string]interface{}) map[string]interface{} {
+	if len(obj) == 0 {
+		return map[string]interface{}{}
+	}
+
+	stripped := runtime.DeepCopyJSON(obj)
+	for _, field := range diffNoiseFields {
+		unstructured.RemoveNestedField(stripped, field...)
+	}
+	return stripped
+}
+
+// unifiedDiff renders the difference between two objects as a unified diff of their YAML form
+func unifiedDiff(before, after map[string]interface{}, fromFile, toFile string) (string, error) {
+	beforeYaml, err := diffYaml(before)
+	if err != nil {
+		return "", err
+	}
+	afterYaml, err := diffYaml(after)
+	if err != nil {
+		return "", err
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(beforeYaml),
+		B:        difflib.SplitLines(afterYaml),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+}
+
+// diffYaml renders an object as YAML for diffing, an empty object renders as an empty document
+func diffYaml(obj map[string]interface{}) (string, error) {
+	if len(obj) == 0 {
+		return "", nil
+	}
+
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize object: %w", err)
+	}
+	return string(data), nil
+}
+
+// diffFields walks two JSON-like values and returns the changed field paths in sorted order
+func diffFields(path string, before, after interface{}) []FieldChange {
+	beforeMap, beforeIsMap := before.(map[string]interface{})
+	afterMap, afterIsMap := after.(map[string]interface{})
+	if !beforeIsMap || !afterIsMap {
+		if reflect.DeepEqual(before, after) {
+			return nil
+		}
+		return []FieldChange{{Path: path, Operation: "replace", Old: before, New: after}}
+	}
+
+	keys := make([]string, 0, len(beforeMap)+len(afterMap))
+	for key := range beforeMap {
+		keys = append(keys, key)
+	}
+	for key := range afterMap {
+		if _, ok := beforeMap[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []FieldChange
+	for _, key := range keys {
+		childPath := diffFieldPath(path, key)
+		beforeValue, inBefore := beforeMap[key]
+		afterValue, inAfter := afterMap[key]
+		switch {
+		case !inBefore:
+			changes = append(changes, FieldChange{Path: childPath, Operation: "add", New: afterValue})
+		case !inAfter:
+			changes = append(changes, FieldChange{Path: childPath, Operation: "remove", Old: beforeValue})
+		default:
+			changes = append(changes, diffFields(childPath, beforeValue, afterValue)...)
+		}
+	}
+
+	return changes
+}
+
+// diffFieldPath appends a key to a dotted field path, quoting keys that contain dots
+func diffFieldPath(path, key string) string {
+	if strings.Contains(key, ".") {
+		key = fmt.Sprintf("[%q]", key)
+		return path + key
+	}
+	if path == "" {
+		return "." + key
+	}
+	return path + "." + key
+}
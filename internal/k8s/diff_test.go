@@ -0,0 +1,49 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestDiffResourceUsesManifestVersion(t *testing.T) {
+	client, applied := newFakeApplyVersionClient()
+	var read []schema.GroupVersionResource
+	client.dynamicClient.(*dynamicfake.FakeDynamicClient).PrependReactor("get", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		read = append(read, action.GetResource())
+		return false, nil, nil
+	})
+
+	diff, err := client.DiffResource(context.Background(), ResourceType{Name: "hpa"}, "", hpaV1Manifest, "", DiffFormatStructured, false)
+	if err != nil {
+		t.Fatalf("DiffResource returned error: %v", err)
+	}
+	if diff.Exists || !diff.Changed {
+		t.Errorf("diff of a new object = exists %v, changed %v, want a changed new object", diff.Exists, diff.Changed)
+	}
+
+	want := schema.GroupVersionResource{Group: "autoscaling", Version: "v1", Resource: "horizontalpodautoscalers"}
+	if len(read) != 1 || read[0] != want {
+		t.Errorf("live object read from %v, want %v", read, want)
+	}
+	if len(*applied) != 1 || (*applied)[0] != want {
+		t.Errorf("dry-run applied to %v, want %v", *applied, want)
+	}
+}
+
+func TestDiffResourceRejectsMismatchedType(t *testing.T) {
+	client, applied := newFakeApplyVersionClient()
+
+	_, err := client.DiffResource(context.Background(), ResourceType{Name: "configmaps"}, "", hpaV1Manifest, "", DiffFormatUnified, false)
+	if err == nil || !strings.Contains(err.Error(), "kind HorizontalPodAutoscaler in the manifest does not match") {
+		t.Fatalf("DiffResource error = %v, want a kind mismatch", err)
+	}
+	if len(*applied) != 0 {
+		t.Errorf("mismatched manifest was applied to %v", *applied)
+	}
+}
@@ -0,0 +1,338 @@
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/restmapper"
+)
+
+const (
+	// DefaultDiscoveryCacheTTL is how long cached API discovery data is used before it is refreshed
+	DefaultDiscoveryCacheTTL = 10 * time.Minute
+	// minDiscoveryInvalidateInterval limits how often a lookup miss may force a discovery refresh
+	minDiscoveryInvalidateInterval = 5 * time.Second
+)
+
+// discoveryCache caches API discovery results in memory and exposes a RESTMapper backed by them.
+// It is shared by all tool handlers through the Client.
+type discoveryCache struct {
+	client discovery.CachedDiscoveryInterface
+	mapper *restmapper.DeferredDiscoveryRESTMapper
+	ttl    time.Duration
+
+	mu          sync.Mutex
+	refreshedAt time.Time
+}
+
+// newDiscoveryCache creates a discovery cache on top of the given discovery client
+func newDiscoveryCache(delegate discovery.DiscoveryInterface, ttl time.Duration) *discoveryCache {
+	cached := memory.NewMemCacheClient(delegate)
+	return &discoveryCache{
+		client:      cached,
+		mapper:      restmapper.NewDeferredDiscoveryRESTMapper(cached),
+		ttl:         ttl,
+		refreshedAt: time.Now(),
+	}
+}
+
+// invalidate drops all cached discovery data so that it is fetched again on next use
+func (d *discoveryCache) invalidate() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.invalidateLocked()
+}
+
+// invalidateLocked drops all cached discovery data, the caller must hold d.mu
+func (d *discoveryCache) invalidateLocked() {
+	// Reset also invalidates the underlying cached discovery client
+	d.mapper.Reset()
+	d.refreshedAt = time.Now()
+}
+
+// expireIfStale invalidates the cache when it is older than the configured TTL
+func (d *discoveryCache) expireIfStale() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.ttl > 0 && time.Since(d.refreshedAt) > d.ttl {
+		d.invalidateLocked()
+	}
+}
+
+// invalidateOnMiss invalidates the cache after a failed lookup, so that newly installed
+// CRDs can be resolved. It returns false if the cache was refreshed too recently.
+func (d *discoveryCache) invalidateOnMiss() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if time.Since(d.refreshedAt) < minDiscoveryInvalidateInterval {
+		return false
+	}
+	d.invalidateLocked()
+	return true
+}
+
+// serverPreferredResources returns the preferred version of every resource in the cluster
+func (d *discoveryCache) serverPreferredResources() ([]*metav1.APIResourceList, error) {
+	d.expireIfStale()
+
+	resourceLists, err := d.client.ServerPreferredResources()
+	if err != nil {
+		// Handle partial errors, some resources may not be accessible
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return nil, fmt.Errorf("failed to get API resources: %w", err)
+		}
+	}
+
+	return resourceLists, nil
+}
+
+// InvalidateDiscoveryCache drops cached API discovery data, forcing it to be fetched again on next use
+func (c *Client) InvalidateDiscoveryCache() {
+	c.discovery.invalidate()
+}
+
+// ResourceType identifies a resource type the way kubectl does. Name may be a kind, a plural or
+// singular resource name, a short name, or any of these qualified by group (e.g. certificates.cert-manager.io).
+type ResourceType struct {
+	// Kind, resource name or short name, optionally in name.group form
+	Name string
+	// APIVersion restricts the match to a group/version (e.g. apps/v1, or v1 for the core group)
+	APIVersion string
+	// Group restricts the match to an API group (e.g. apps, cert-manager.io)
+	Group string
+}
+
+// String returns the resource type as given by the caller
+func (t ResourceType) String() string {
+	switch {
+	case t.APIVersion != "":
+		return fmt.Sprintf("%s (%s)", t.Name, t.APIVersion)
+	case t.Group != "":
+		return fmt.Sprintf("%s (%s)", t.Name, t.Group)
+	default:
+		return t.Name
+	}
+}
+
+// resourceQuery is a parsed ResourceType
+type resourceQuery struct {
+	name     string
+	group    string
+	groupSet bool
+	version  string
+}
+
+// parse normalizes the resource type into a query, checking that the group constraints agree
+func (t ResourceType) parse() (*resourceQuery, error) {
+	name := strings.TrimSpace(t.Name)
+	if name == "" {
+		return nil, fmt.Errorf("resource type must not be empty")
+	}
+
+	q := &resourceQuery{name: name}
+	setGroup := func(group string) error {
+		if q.groupSet && q.group != group {
+			return fmt.Errorf("conflicting API groups %q and %q for resource type %s", q.group, group, t)
+		}
+		q.group = group
+		q.groupSet = true
+		return nil
+	}
+
+	// kind.group syntax, kinds and resource names never contain dots
+	if idx := strings.Index(name, "."); idx > 0 {
+		q.name = name[:idx]
+		if err := setGroup(name[idx+1:]); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.APIVersion != "" {
+		gv, err := schema.ParseGroupVersion(t.APIVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid apiVersion %q: %w", t.APIVersion, err)
+		}
+		if err := setGroup(gv.Group); err != nil {
+			return nil, err
+		}
+		q.version = gv.Version
+	}
+
+	if t.Group != "" {
+		if err := setGroup(t.Group); err != nil {
+			return nil, err
+		}
+	}
+
+	return q, nil
+}
+
+// matches reports whether an API resource is named by the query's name
+func (q *resourceQuery) matches(resource metav1.APIResource) bool {
+	if strings.EqualFold(resource.Kind, q.name) {
+		return true
+	}
+
+	lower := strings.ToLower(q.name)
+	if resource.Name == lower || resource.SingularName == lower {
+		return true
+	}
+	for _, shortName := range resource.ShortNames {
+		if shortName == lower {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findResourceMapping resolves a resource type to its REST mapping
+func (c *Client) findResourceMapping(resourceType ResourceType) (*meta.RESTMapping, error) {
+	query, err := resourceType.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, err := c.lookupResourceMapping(query, resourceType)
+	if err == nil || !errors.Is(err, errResourceNotFound) {
+		return mapping, err
+	}
+
+	// The type may belong to a CRD installed after the cache was filled, refresh and retry once
+	if c.discovery.invalidateOnMiss() {
+		return c.lookupResourceMapping(query, resourceType)
+	}
+
+	return nil, err
+}
+
+// errResourceNotFound is returned when no API resource matches a resource type
+var errResourceNotFound = errors.New("resource type not found")
+
+// lookupResourceMapping resolves a parsed resource type against the cached discovery data
+func (c *Client) lookupResourceMapping(query *resourceQuery, resourceType ResourceType) (*meta.RESTMapping, error) {
+	var resourceLists []*metav1.APIResourceList
+	var err error
+	if query.version != "" {
+		// A specific version may not be the preferred one, so search all versions
+		c.discovery.expireIfStale()
+		_, resourceLists, err = c.discovery.client.ServerGroupsAndResources()
+		if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+			return nil, fmt.Errorf("failed to get API resources: %w", err)
+		}
+	} else {
+		resourceLists, err = c.discovery.serverPreferredResources()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var candidates []schema.GroupVersionKind
+	seen := map[schema.GroupVersionKind]bool{}
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		if query.groupSet && gv.Group != query.group {
+			continue
+		}
+		if query.version != "" && gv.Version != query.version {
+			continue
+		}
+
+		for _, resource := range resourceList.APIResources {
+			// Skip sub-resources such as deployments/scale
+			if strings.Contains(resource.Name, "/") || !query.matches(resource) {
+				continue
+			}
+
+			gvk := gv.WithKind(resource.Kind)
+			if !seen[gvk] {
+				seen[gvk] = true
+				candidates = append(candidates, gvk)
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: %s", errResourceNotFound, resourceType)
+	}
+
+	candidates = c.discovery.preferredCandidates(candidates)
+	if len(candidates) > 1 {
+		names := make([]string, 0, len(candidates))
+		for _, gvk := range candidates {
+			names = append(names, fmt.Sprintf("%s (apiVersion: %s)", gvk.Kind, gvk.GroupVersion()))
+		}
+		return nil, fmt.Errorf("resource type %s is ambiguous, specify apiVersion or group to choose one of: %s",
+			resourceType, strings.Join(names, ", "))
+	}
+
+	mapping, err := c.discovery.mapper.RESTMapping(candidates[0].GroupKind(), candidates[0].Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map resource type %s: %w", resourceType, err)
+	}
+	return mapping, nil
+}
+
+// preferredCandidates narrows the kinds matching a name to those of the highest priority group,
+// like kubectl resolves pods to the core group although metrics.k8s.io also serves pods
+func (d *discoveryCache) preferredCandidates(candidates []schema.GroupVersionKind) []schema.GroupVersionKind {
+	if len(candidates) < 2 {
+		return candidates
+	}
+
+	priorities := d.groupPriorities()
+	best := -1
+	var preferred []schema.GroupVersionKind
+	for _, gvk := range candidates {
+		priority := priorities(gvk.Group)
+		switch {
+		case best == -1 || priority < best:
+			best = priority
+			preferred = []schema.GroupVersionKind{gvk}
+		case priority == best:
+			preferred = append(preferred, gvk)
+		}
+	}
+	return preferred
+}
+
+// groupPriorities returns the priority of API groups, lower is preferred. The core group comes
+// first, then the built-in groups in the order the server lists them, which follows the API
+// server's group priorities. Other groups, such as CRDs and aggregated APIs, share the lowest
+// priority so that a name served by several of them stays ambiguous.
+func (d *discoveryCache) groupPriorities() func(group string) int {
+	order := map[string]int{"": 0}
+	if groups, err := d.client.ServerGroups(); err == nil {
+		for _, group := range groups.Groups {
+			if _, ok := order[group.Name]; !ok && scheme.Scheme.IsGroupRegistered(group.Name) {
+				order[group.Name] = len(order)
+			}
+		}
+	}
+
+	return func(group string) int {
+		if priority, ok := order[group]; ok {
+			return priority
+		}
+		if scheme.Scheme.IsGroupRegistered(group) {
+			// A built-in group missing from the group list, e.g. after a failed discovery request
+			return len(order)
+		}
+		return len(order) + 1
+	}
+}
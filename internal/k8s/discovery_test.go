@@ -0,0 +1,137 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// newFakeDiscoveryClient returns a client resolving resource types against the given API resources
+func newFakeDiscoveryClient(resources []*metav1.APIResourceList) *Client {
+	fake := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: resources}}
+	return &Client{discovery: newDiscoveryCache(fake, DefaultDiscoveryCacheTTL)}
+}
+
+// testAPIResources is a cluster with metrics-server installed, which also serves pods and nodes,
+// and two CRDs that share a resource name
+var testAPIResources = []*metav1.APIResourceList{
+	{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{
+			{Name: "pods", SingularName: "pod", Kind: "Pod", Namespaced: true, ShortNames: []string{"po"}},
+			{Name: "nodes", SingularName: "node", Kind: "Node", ShortNames: []string{"no"}},
+			{Name: "events", SingularName: "event", Kind: "Event", Namespaced: true, ShortNames: []string{"ev"}},
+			{Name: "namespaces", SingularName: "namespace", Kind: "Namespace", ShortNames: []string{"ns"}},
+			{Name: "configmaps", SingularName: "configmap", Kind: "ConfigMap", Namespaced: true, ShortNames: []string{"cm"}},
+		},
+	},
+	{
+		GroupVersion: "apps/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "deployments", SingularName: "deployment", Kind: "Deployment", Namespaced: true, ShortNames: []string{"deploy"}},
+		},
+	},
+	{
+		GroupVersion: "autoscaling/v2",
+		APIResources: []metav1.APIResource{
+			{Name: "horizontalpodautoscalers", SingularName: "horizontalpodautoscaler", Kind: "HorizontalPodAutoscaler", Namespaced: true, ShortNames: []string{"hpa"}},
+		},
+	},
+	{
+		GroupVersion: "autoscaling/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "horizontalpodautoscalers", SingularName: "horizontalpodautoscaler", Kind: "HorizontalPodAutoscaler", Namespaced: true, ShortNames: []string{"hpa"}},
+		},
+	},
+	{
+		GroupVersion: "rbac.authorization.k8s.io/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "clusterroles", SingularName: "clusterrole", Kind: "ClusterRole"},
+		},
+	},
+	{
+		GroupVersion: "events.k8s.io/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "events", SingularName: "event", Kind: "Event", Namespaced: true, ShortNames: []string{"ev"}},
+		},
+	},
+	{
+		GroupVersion: "metrics.k8s.io/v1beta1",
+		APIResources: []metav1.APIResource{
+			{Name: "pods", SingularName: "", Kind: "PodMetrics", Namespaced: true},
+			{Name: "nodes", SingularName: "", Kind: "NodeMetrics"},
+		},
+	},
+	{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "widgets", SingularName: "widget", Kind: "Widget", Namespaced: true},
+		},
+	},
+	{
+		GroupVersion: "example.org/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "widgets", SingularName: "widget", Kind: "Widget", Namespaced: true},
+		},
+	},
+}
+
+func TestFindResourceMappingPriority(t *testing.T) {
+	client := newFakeDiscoveryClient(testAPIResources)
+
+	tests := []struct {
+		name         string
+		resourceType ResourceType
+		want         schema.GroupVersionKind
+	}{
+		{"core pods over metrics", ResourceType{Name: "pods"}, schema.GroupVersionKind{Version: "v1", Kind: "Pod"}},
+		{"core nodes over metrics", ResourceType{Name: "nodes"}, schema.GroupVersionKind{Version: "v1", Kind: "Node"}},
+		{"short name", ResourceType{Name: "po"}, schema.GroupVersionKind{Version: "v1", Kind: "Pod"}},
+		{"core events over events.k8s.io", ResourceType{Name: "events"}, schema.GroupVersionKind{Version: "v1", Kind: "Event"}},
+		{"kind", ResourceType{Name: "Event"}, schema.GroupVersionKind{Version: "v1", Kind: "Event"}},
+		{"explicit group", ResourceType{Name: "events", Group: "events.k8s.io"}, schema.GroupVersionKind{Group: "events.k8s.io", Version: "v1", Kind: "Event"}},
+		{"explicit metrics group", ResourceType{Name: "pods.metrics.k8s.io"}, schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetrics"}},
+		{"metrics kind", ResourceType{Name: "NodeMetrics"}, schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "NodeMetrics"}},
+		{"built-in group", ResourceType{Name: "deploy"}, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}},
+		{"explicit apiVersion", ResourceType{Name: "widgets", APIVersion: "example.org/v1"}, schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Widget"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping, err := client.findResourceMapping(tt.resourceType)
+			if err != nil {
+				t.Fatalf("findResourceMapping(%s) returned error: %v", tt.resourceType, err)
+			}
+			if mapping.GroupVersionKind != tt.want {
+				t.Errorf("findResourceMapping(%s) = %s, want %s", tt.resourceType, mapping.GroupVersionKind, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindResourceMappingAmbiguous(t *testing.T) {
+	client := newFakeDiscoveryClient(testAPIResources)
+
+	_, err := client.findResourceMapping(ResourceType{Name: "widgets"})
+	if err == nil {
+		t.Fatal("findResourceMapping(widgets) succeeded, want an ambiguity error")
+	}
+	for _, want := range []string{"ambiguous", "example.com/v1", "example.org/v1"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+}
+
+func TestFindResourceMappingNotFound(t *testing.T) {
+	client := newFakeDiscoveryClient(testAPIResources)
+
+	_, err := client.findResourceMapping(ResourceType{Name: "gadgets"})
+	if err == nil || !strings.Contains(err.Error(), errResourceNotFound.Error()) {
+		t.Fatalf("findResourceMapping(gadgets) error = %v, want %v", err, errResourceNotFound)
+	}
+}
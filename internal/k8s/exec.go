@@ -0,0 +1,192 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+)
+
+const (
+	// DefaultContainerAnnotation selects the default container of a pod, as used by kubectl
+	DefaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+	// DefaultExecTimeout is the default time limit of a command run in a pod
+	DefaultExecTimeout = 30 * time.Second
+	// DefaultExecMaxOutputBytes is the default size limit of each of stdout and stderr of a command
+	DefaultExecMaxOutputBytes = 64 * 1024
+)
+
+// ExecResult is the outcome of a command run in a pod container
+type ExecResult struct {
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+	Container string `json:"container"`
+	Stdout    string `json:"stdout"`
+	Stderr    string `json:"stderr"`
+	// ExitCode is -1 if the command did not finish
+	ExitCode        int  `json:"exitCode"`
+	StdoutTruncated bool `json:"stdoutTruncated,omitempty"`
+	StderrTruncated bool `json:"stderrTruncated,omitempty"`
+	TimedOut        bool `json:"timedOut,omitempty"`
+}
+
+// limitedBuffer keeps the first max bytes written to it and discards the rest, so a noisy command
+// keeps running instead of failing on a short write
+type limitedBuffer struct {
+	buf       strings.Builder
+	max       int
+	truncated bool
+}
+
+// Write implements io.Writer
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	remaining := b.max - b.buf.Len()
+	if b.max > 0 && len(p) > remaining {
+		if remaining > 0 {
+			b.buf.Write(p[:remaining])
+		}
+		b.truncated = true
+		return len(p), nil
+	}
+	b.buf.Write(p)
+	return len(p), nil
+}
+
+// ExecInPod runs a non-interactive command in a pod container and returns its output and exit code.
+// The command is stopped after the timeout, and stdout and stderr are each capped at maxOutputBytes,
+// 0 disables the cap. A command exiting with a non-zero code is not an error.
+func (c *Client) ExecInPod(ctx context.Context, namespace, podName, container string, command []string, stdin string, timeout time.Duration, maxOutputBytes int) (*ExecResult, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("command must not be empty")
+	}
+
+	container, err := c.resolveContainer(ctx, namespace, podName, container)
+	if err != nil {
+		return nil, err
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultExecTimeout
+	}
+	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stdout := &limitedBuffer{max: maxOutputBytes}
+	stderr := &limitedBuffer{max: maxOutputBytes}
+	var stdinReader io.Reader
+	if stdin != "" {
+		stdinReader = strings.NewReader(stdin)
+	}
+
+	result := &ExecResult{
+		Pod:       podName,
+		Namespace: namespace,
+		Container: container,
+	}
+
+	err = c.streamExec(execCtx, namespace, podName, container, command, stdinReader, stdout, stderr)
+	result.Stdout = stdout.buf.String()
+	result.Stderr = stderr.buf.String()
+	result.StdoutTruncated = stdout.truncated
+	result.StderrTruncated = stderr.truncated
+
+	var exitErr exec.ExitError
+	switch {
+	case err == nil:
+		result.ExitCode = 0
+	case errors.As(err, &exitErr) && exitErr.Exited():
+		result.ExitCode = exitErr.ExitStatus()
+	case execCtx.Err() != nil && ctx.Err() == nil:
+		result.ExitCode = -1
+		result.TimedOut = true
+	default:
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// streamExec runs a command in a pod container, connecting the given streams. It uses the
+// WebSocket protocol and falls back to SPDY for API servers that do not support it.
+func (c *Client) streamExec(ctx context.Context, namespace, podName, container string, command []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	req := c.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(podName).
+		Namespace(namespace).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     stdin != nil,
+			Stdout:    stdout != nil,
+			Stderr:    stderr != nil,
+		}, scheme.ParameterCodec)
+
+	spdyExecutor, err := remotecommand.NewSPDYExecutor(c.restConfig, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("failed to create exec executor: %w", err)
+	}
+	websocketExecutor, err := remotecommand.NewWebSocketExecutor(c.restConfig, "GET", req.URL().String())
+	if err != nil {
+		return fmt.Errorf("failed to create exec executor: %w", err)
+	}
+	executor, err := remotecommand.NewFallbackExecutor(websocketExecutor, spdyExecutor, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create exec executor: %w", err)
+	}
+
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+	if err != nil {
+		var exitErr exec.ExitError
+		if errors.As(err, &exitErr) {
+			return err
+		}
+		return fmt.Errorf("failed to exec in pod: %w", err)
+	}
+
+	return nil
+}
+
+// resolveContainer returns the given container name, or the default container of the pod if it is empty
+func (c *Client) resolveContainer(ctx context.Context, namespace, podName, container string) (string, error) {
+	if container != "" {
+		return container, nil
+	}
+
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get pod: %w", err)
+	}
+	return defaultContainer(pod)
+}
+
+// defaultContainer returns the container kubectl picks when none is given: the one named by the
+// default-container annotation, otherwise the first container of the pod
+func defaultContainer(pod *corev1.Pod) (string, error) {
+	if name := pod.Annotations[DefaultContainerAnnotation]; name != "" {
+		for _, container := range pod.Spec.Containers {
+			if container.Name == name {
+				return name, nil
+			}
+		}
+	}
+	if len(pod.Spec.Containers) == 0 {
+		return "", fmt.Errorf("pod %s has no containers", pod.Name)
+	}
+	return pod.Spec.Containers[0].Name, nil
+}
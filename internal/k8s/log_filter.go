@@ -0,0 +1,140 @@
+package k8s
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// logSeparator separates non-adjacent groups of lines selected by a logFilter
+const logSeparator = "--"
+
+// separatorWriter is implemented by writers that tell group separators apart from log lines
+type separatorWriter interface {
+	writeSeparator() error
+}
+
+// logFilter selects log lines matching a regular expression, like grep, while the logs are streamed
+type logFilter struct {
+	pattern *regexp.Regexp
+	// invert selects the lines not matching the pattern
+	invert bool
+	// contextLines is the number of lines printed before and after every selected line
+	contextLines int
+	// timestamps strips the kubelet timestamp before matching a line
+	timestamps bool
+}
+
+// newLogFilter returns the filter described by the log options, or nil if they do not filter
+func newLogFilter(opts PodLogOptions) (*logFilter, error) {
+	if opts.Grep == "" {
+		if opts.Invert || opts.ContextLines > 0 {
+			return nil, fmt.Errorf("invert and context lines require a grep pattern")
+		}
+		return nil, nil
+	}
+	if opts.ContextLines < 0 {
+		return nil, fmt.Errorf("context lines must not be negative: %d", opts.ContextLines)
+	}
+
+	pattern, err := regexp.Compile(opts.Grep)
+	if err != nil {
+		return nil, fmt.Errorf("invalid grep pattern %q: %w", opts.Grep, err)
+	}
+
+	return &logFilter{
+		pattern:      pattern,
+		invert:       opts.Invert,
+		contextLines: opts.ContextLines,
+		timestamps:   opts.Timestamps,
+	}, nil
+}
+
+// selects reports whether a line is selected by the filter
+func (f *logFilter) selects(line string) bool {
+	if f.timestamps {
+		if ts, rest, ok := strings.Cut(line, " "); ok {
+			if _, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+				line = rest
+			}
+		}
+	}
+	return f.pattern.MatchString(line) != f.invert
+}
+
+// copy reads lines from r and writes the selected lines and their context to w. Non-adjacent groups
+// of lines are separated by a "--" line, as grep does.
+func (f *logFilter) copy(w io.Writer, r io.Reader) error {
+	reader := bufio.NewReader(r)
+	// before holds up to contextLines lines preceding the next selected line
+	before := make([]string, 0, f.contextLines)
+	after := 0
+	printed := false
+	// skipped records whether lines were dropped since the last printed line
+	skipped := false
+
+	write := func(line string) error {
+		if printed && skipped {
+			var err error
+			if sw, ok := w.(separatorWriter); ok {
+				err = sw.writeSeparator()
+			} else {
+				_, err = io.WriteString(w, logSeparator+"\n")
+			}
+			if err != nil {
+				return err
+			}
+		}
+		printed = true
+		skipped = false
+		_, err := io.WriteString(w, line)
+		return err
+	}
+
+	for {
+		line, readErr := reader.ReadString('\n')
+		if line != "" {
+			if !strings.HasSuffix(line, "\n") {
+				line += "\n"
+			}
+
+			switch {
+			case f.selects(strings.TrimSuffix(line, "\n")):
+				for _, contextLine := range before {
+					if err := write(contextLine); err != nil {
+						return err
+					}
+				}
+				before = before[:0]
+				if err := write(line); err != nil {
+					return err
+				}
+				after = f.contextLines
+			case after > 0:
+				if err := write(line); err != nil {
+					return err
+				}
+				after--
+			case f.contextLines > 0:
+				if len(before) == f.contextLines {
+					before = before[1:]
+					skipped = true
+				}
+				before = append(before, line)
+			default:
+				skipped = true
+			}
+		}
+
+		if readErr != nil {
+			if errors.Is(readErr, io.EOF) {
+				return nil
+			}
+			return readErr
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogFilterCopy(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  PodLogOptions
+		input string
+		want  string
+	}{
+		{
+			name:  "matching lines",
+			opts:  PodLogOptions{Grep: "error"},
+			input: "a\nerror 1\nb\nerror 2\n",
+			want:  "error 1\n--\nerror 2\n",
+		},
+		{
+			name:  "adjacent matches are not separated",
+			opts:  PodLogOptions{Grep: "error"},
+			input: "error 1\nerror 2\nb\n",
+			want:  "error 1\nerror 2\n",
+		},
+		{
+			name:  "inverted",
+			opts:  PodLogOptions{Grep: "debug", Invert: true},
+			input: "debug 1\ninfo 1\ndebug 2\ninfo 2\ninfo 3\n",
+			want:  "info 1\n--\ninfo 2\ninfo 3\n",
+		},
+		{
+			name:  "context before and after",
+			opts:  PodLogOptions{Grep: "error", ContextLines: 1},
+			input: "a\nb\nerror\nc\nd\n",
+			want:  "b\nerror\nc\n",
+		},
+		{
+			name:  "overlapping context windows are merged",
+			opts:  PodLogOptions{Grep: "error", ContextLines: 2},
+			input: "a\nerror 1\nb\nc\nerror 2\nd\n",
+			want:  "a\nerror 1\nb\nc\nerror 2\nd\n",
+		},
+		{
+			name:  "touching context windows are not separated",
+			opts:  PodLogOptions{Grep: "error", ContextLines: 1},
+			input: "error 1\nb\nc\nerror 2\n",
+			want:  "error 1\nb\nc\nerror 2\n",
+		},
+		{
+			name:  "separated context windows",
+			opts:  PodLogOptions{Grep: "error", ContextLines: 1},
+			input: "error 1\nb\nc\nd\nerror 2\ne\n",
+			want:  "error 1\nb\n--\nd\nerror 2\ne\n",
+		},
+		{
+			name:  "context at the start of the logs",
+			opts:  PodLogOptions{Grep: "error", ContextLines: 3},
+			input: "a\nerror\n",
+			want:  "a\nerror\n",
+		},
+		{
+			name:  "last line without newline",
+			opts:  PodLogOptions{Grep: "error"},
+			input: "a\nerror",
+			want:  "error\n",
+		},
+		{
+			name:  "no match",
+			opts:  PodLogOptions{Grep: "error", ContextLines: 1},
+			input: "a\nb\n",
+			want:  "",
+		},
+		{
+			name:  "timestamps are not matched",
+			opts:  PodLogOptions{Grep: "^ready", Timestamps: true},
+			input: "2026-01-02T15:04:05Z starting\n2026-01-02T15:04:06Z ready\n",
+			want:  "2026-01-02T15:04:06Z ready\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := newLogFilter(tt.opts)
+			if err != nil {
+				t.Fatalf("newLogFilter returned error: %v", err)
+			}
+
+			var out strings.Builder
+			if err := filter.copy(&out, strings.NewReader(tt.input)); err != nil {
+				t.Fatalf("copy returned error: %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("copy wrote %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLogFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    PodLogOptions
+		wantNil bool
+		wantErr string
+	}{
+		{name: "no grep", opts: PodLogOptions{}, wantNil: true},
+		{name: "grep", opts: PodLogOptions{Grep: "error"}},
+		{name: "invert without grep", opts: PodLogOptions{Invert: true}, wantErr: "invert and context lines require a grep pattern"},
+		{name: "context without grep", opts: PodLogOptions{ContextLines: 2}, wantErr: "invert and context lines require a grep pattern"},
+		{name: "negative context", opts: PodLogOptions{Grep: "error", ContextLines: -1}, wantErr: "context lines must not be negative: -1"},
+		{name: "invalid pattern", opts: PodLogOptions{Grep: "("}, wantErr: `invalid grep pattern "("`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := newLogFilter(tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("newLogFilter error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newLogFilter returned error: %v", err)
+			}
+			if (filter == nil) != tt.wantNil {
+				t.Errorf("newLogFilter = %v, want nil %v", filter, tt.wantNil)
+			}
+		})
+	}
+}
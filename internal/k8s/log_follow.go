@@ -0,0 +1,146 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	// FollowStoppedDuration means the follow duration elapsed
+	FollowStoppedDuration = "duration"
+	// FollowStoppedLineLimit means the line budget was used up
+	FollowStoppedLineLimit = "line_limit"
+	// FollowStoppedStreamEnded means the container stopped and its log stream ended
+	FollowStoppedStreamEnded = "stream_ended"
+	// FollowStoppedCancelled means the caller cancelled the request
+	FollowStoppedCancelled = "cancelled"
+)
+
+// errLineLimit stops a followed log stream once the line budget is used up
+var errLineLimit = errors.New("line limit reached")
+
+// lineWriter splits the bytes written to it into lines and passes each complete line to onLine.
+// Group separators written by a logFilter are passed to onSeparator.
+type lineWriter struct {
+	partial     strings.Builder
+	onLine      func(line string) error
+	onSeparator func() error
+}
+
+// Write implements io.Writer
+func (w *lineWriter) Write(p []byte) (int, error) {
+	data := string(p)
+	for {
+		i := strings.IndexByte(data, '\n')
+		if i < 0 {
+			w.partial.WriteString(data)
+			return len(p), nil
+		}
+		w.partial.WriteString(data[:i])
+		line := w.partial.String()
+		w.partial.Reset()
+		if err := w.onLine(line); err != nil {
+			return len(p), err
+		}
+		data = data[i+1:]
+	}
+}
+
+// writeSeparator implements separatorWriter
+func (w *lineWriter) writeSeparator() error {
+	return w.onSeparator()
+}
+
+// flush passes a trailing line without a newline to onLine
+func (w *lineWriter) flush() error {
+	if w.partial.Len() == 0 {
+		return nil
+	}
+	line := w.partial.String()
+	w.partial.Reset()
+	return w.onLine(line)
+}
+
+// FollowPodLogs streams new log lines of a pod container and passes every line selected by the
+// log options to onLine, and the separators between non-adjacent groups of grep context lines to
+// onSeparator, which may be nil. It stops when the duration elapses, after maxLines log lines,
+// when the log stream ends or when ctx is cancelled, and returns why it stopped.
+func (c *Client) FollowPodLogs(ctx context.Context, namespace, podName string, opts PodLogOptions, duration time.Duration, maxLines int, onLine func(line string), onSeparator func()) (string, error) {
+	if opts.AllContainers {
+		return "", fmt.Errorf("following logs of all containers is not supported, select a container")
+	}
+	if opts.Previous {
+		return "", fmt.Errorf("logs of a previous container instance cannot be followed")
+	}
+	if opts.SinceSeconds > 0 && opts.SinceTime != nil {
+		return "", fmt.Errorf("since seconds and since time cannot be used together")
+	}
+	filter, err := newLogFilter(opts)
+	if err != nil {
+		return "", err
+	}
+
+	followCtx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	logOptions := opts.podLogOptions(opts.Container)
+	logOptions.Follow = true
+
+	stream, err := c.clientset.CoreV1().Pods(namespace).GetLogs(podName, logOptions).Stream(followCtx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get pod logs: %w", err)
+	}
+	defer func(stream io.ReadCloser) {
+		err := stream.Close()
+		if err != nil {
+			log.Printf("failed to close pod logs stream: %v", err)
+		}
+	}(stream)
+
+	lines := 0
+	writer := &lineWriter{
+		onLine: func(line string) error {
+			onLine(line)
+			lines++
+			if maxLines > 0 && lines >= maxLines {
+				return errLineLimit
+			}
+			return nil
+		},
+		// Separators are passed on but do not count against the line budget
+		onSeparator: func() error {
+			if onSeparator != nil {
+				onSeparator()
+			}
+			return nil
+		},
+	}
+
+	if filter != nil {
+		err = filter.copy(writer, stream)
+	} else {
+		_, err = io.Copy(writer, stream)
+	}
+	if !errors.Is(err, errLineLimit) {
+		// The last line may lack a newline when the stream ends or the duration elapses
+		_ = writer.flush()
+	}
+
+	switch {
+	case errors.Is(err, errLineLimit):
+		return FollowStoppedLineLimit, nil
+	case ctx.Err() != nil:
+		return FollowStoppedCancelled, nil
+	case followCtx.Err() != nil:
+		return FollowStoppedDuration, nil
+	case err != nil:
+		return "", fmt.Errorf("failed to read logs: %w", err)
+	default:
+		return FollowStoppedStreamEnded, nil
+	}
+}
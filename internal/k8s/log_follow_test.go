@@ -0,0 +1,72 @@
+package k8s
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLineWriterWithFilter(t *testing.T) {
+	filter, err := newLogFilter(PodLogOptions{Grep: "error", ContextLines: 1})
+	if err != nil {
+		t.Fatalf("newLogFilter returned error: %v", err)
+	}
+
+	var lines []string
+	separators := 0
+	writer := &lineWriter{
+		onLine: func(line string) error {
+			lines = append(lines, line)
+			return nil
+		},
+		onSeparator: func() error {
+			separators++
+			return nil
+		},
+	}
+
+	input := "a\nerror 1\nb\nc\nd\nerror 2"
+	if err := filter.copy(writer, strings.NewReader(input)); err != nil {
+		t.Fatalf("copy returned error: %v", err)
+	}
+	if err := writer.flush(); err != nil {
+		t.Fatalf("flush returned error: %v", err)
+	}
+
+	want := []string{"a", "error 1", "b", "d", "error 2"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+	if separators != 1 {
+		t.Errorf("separators = %d, want 1", separators)
+	}
+}
+
+func TestLineWriterFlushesTrailingLine(t *testing.T) {
+	var lines []string
+	writer := &lineWriter{onLine: func(line string) error {
+		lines = append(lines, line)
+		return nil
+	}}
+
+	for _, chunk := range []string{"first\nsec", "ond\nlast without newline"} {
+		if _, err := writer.Write([]byte(chunk)); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+	}
+	if want := []string{"first", "second"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines before flush = %q, want %q", lines, want)
+	}
+
+	if err := writer.flush(); err != nil {
+		t.Fatalf("flush returned error: %v", err)
+	}
+	if want := []string{"first", "second", "last without newline"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines after flush = %q, want %q", lines, want)
+	}
+
+	// A second flush has nothing left to pass on
+	if err := writer.flush(); err != nil || len(lines) != 3 {
+		t.Errorf("second flush passed %d lines, error %v", len(lines)-3, err)
+	}
+}
@@ -0,0 +1,360 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// DefaultWorkloadLogMaxPods is the default number of pods get_workload_logs reads logs from
+	DefaultWorkloadLogMaxPods = 20
+	// DefaultWorkloadLogConcurrency is the default number of log streams read in parallel
+	DefaultWorkloadLogConcurrency = 5
+)
+
+// workloadSelectorKinds lists the kinds whose pods can be selected by GetWorkloadLogs
+var workloadSelectorKinds = map[schema.GroupKind]bool{
+	{Group: appsv1.GroupName, Kind: KindDeployment}:  true,
+	{Group: appsv1.GroupName, Kind: KindStatefulSet}: true,
+	{Group: appsv1.GroupName, Kind: KindDaemonSet}:   true,
+	{Group: appsv1.GroupName, Kind: "ReplicaSet"}:    true,
+	{Group: batchv1.GroupName, Kind: "Job"}:          true,
+}
+
+// PodLogOptions controls which logs are retrieved from a pod
+type PodLogOptions struct {
+	// Container to read logs from, empty selects the default container
+	Container string
+	// AllContainers reads the logs of all init, regular and ephemeral containers, each line prefixed
+	// with the container name. Container is ignored.
+	AllContainers bool
+	// TailLines is the number of lines to read from the end of the logs, a negative value reads all lines
+	TailLines int64
+	// Previous reads the logs of the previous terminated instance of the container
+	Previous bool
+	// SinceSeconds only returns logs newer than this many seconds, 0 disables the limit
+	SinceSeconds int64
+	// SinceTime only returns logs after this time, it cannot be combined with SinceSeconds
+	SinceTime *time.Time
+	// Timestamps prefixes every line with its RFC3339 timestamp
+	Timestamps bool
+	// LimitBytes is the maximum number of bytes read per container, 0 disables the limit
+	LimitBytes int64
+	// Grep is a regular expression selecting the returned lines, empty returns all lines
+	Grep string
+	// Invert returns the lines not matching Grep
+	Invert bool
+	// ContextLines is the number of lines returned before and after every line selected by Grep
+	ContextLines int
+}
+
+// podLogOptions converts the options to the API options for a single container
+func (o PodLogOptions) podLogOptions(container string) *corev1.PodLogOptions {
+	opts := &corev1.PodLogOptions{
+		Container:  container,
+		Previous:   o.Previous,
+		Timestamps: o.Timestamps,
+	}
+	if o.TailLines >= 0 {
+		opts.TailLines = int64Ptr(o.TailLines)
+	}
+	if o.SinceSeconds > 0 {
+		opts.SinceSeconds = int64Ptr(o.SinceSeconds)
+	}
+	if o.SinceTime != nil {
+		sinceTime := metav1.NewTime(*o.SinceTime)
+		opts.SinceTime = &sinceTime
+	}
+	if o.LimitBytes > 0 {
+		opts.LimitBytes = int64Ptr(o.LimitBytes)
+	}
+	return opts
+}
+
+// GetPodLogs retrieves logs from a specific pod
+func (c *Client) GetPodLogs(ctx context.Context, namespace, podName string, opts PodLogOptions) (string, error) {
+	if opts.SinceSeconds > 0 && opts.SinceTime != nil {
+		return "", fmt.Errorf("since seconds and since time cannot be used together")
+	}
+
+	filter, err := newLogFilter(opts)
+	if err != nil {
+		return "", err
+	}
+
+	if !opts.AllContainers {
+		return c.readPodLogs(ctx, namespace, podName, opts.podLogOptions(opts.Container), filter)
+	}
+
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get pod: %w", err)
+	}
+
+	var builder strings.Builder
+	for _, container := range podContainerNames(pod) {
+		logs, err := c.readPodLogs(ctx, namespace, podName, opts.podLogOptions(container), filter)
+		if err != nil {
+			// A container without logs, e.g. one that never ran or has no previous instance,
+			// should not hide the logs of the other containers
+			fmt.Fprintf(&builder, "[%s] %v\n", container, err)
+			continue
+		}
+
+		if logs == "" {
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(logs, "\n"), "\n") {
+			fmt.Fprintf(&builder, "[%s] %s\n", container, line)
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// readPodLogs reads the logs of a single pod container, keeping only the lines selected by the filter if it is set
+func (c *Client) readPodLogs(ctx context.Context, namespace, podName string, opts *corev1.PodLogOptions, filter *logFilter) (string, error) {
+	req := c.clientset.CoreV1().Pods(namespace).GetLogs(podName, opts)
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get pod logs: %w", err)
+	}
+	defer func(stream io.ReadCloser) {
+		err := stream.Close()
+		if err != nil {
+			log.Printf("failed to close pod logs stream: %v", err)
+		}
+	}(stream)
+
+	if filter != nil {
+		var logs strings.Builder
+		if err := filter.copy(&logs, stream); err != nil {
+			return "", fmt.Errorf("failed to read logs: %w", err)
+		}
+		return logs.String(), nil
+	}
+
+	logs, err := io.ReadAll(stream)
+	if err != nil {
+		return "", fmt.Errorf("failed to read logs: %w", err)
+	}
+
+	return string(logs), nil
+}
+
+// podContainerNames returns the names of the init, regular and ephemeral containers of a pod
+func podContainerNames(pod *corev1.Pod) []string {
+	names := make([]string, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers)+len(pod.Spec.EphemeralContainers))
+	for _, container := range pod.Spec.InitContainers {
+		names = append(names, container.Name)
+	}
+	for _, container := range pod.Spec.Containers {
+		names = append(names, container.Name)
+	}
+	for _, container := range pod.Spec.EphemeralContainers {
+		names = append(names, container.Name)
+	}
+	return names
+}
+
+// podLogStream identifies the logs of one container of a pod
+type podLogStream struct {
+	pod       string
+	container string
+}
+
+// prefix returns the pod/container prefix of the lines of a stream
+func (s podLogStream) prefix() string {
+	return fmt.Sprintf("[%s/%s]", s.pod, s.container)
+}
+
+// timestampedLine is a log line with the timestamp the kubelet recorded for it
+type timestampedLine struct {
+	timestamp time.Time
+	prefix    string
+	text      string
+}
+
+// GetWorkloadLogs retrieves the logs of the pods of a workload, or of the pods matching a label
+// selector if resourceType is empty. Logs of up to maxPods pods are read concurrently with at most
+// concurrency streams at a time, and returned interleaved by timestamp with a pod/container prefix.
+func (c *Client) GetWorkloadLogs(ctx context.Context, resourceType ResourceType, name, namespace, labelSelector string, opts PodLogOptions, maxPods, concurrency int) (string, error) {
+	if opts.SinceSeconds > 0 && opts.SinceTime != nil {
+		return "", fmt.Errorf("since seconds and since time cannot be used together")
+	}
+	if maxPods <= 0 {
+		maxPods = DefaultWorkloadLogMaxPods
+	}
+
+	// The kubelet timestamps are needed to interleave the streams, they are stripped again below
+	// unless the caller asked for them
+	streamOpts := opts
+	streamOpts.Timestamps = true
+	filter, err := newLogFilter(streamOpts)
+	if err != nil {
+		return "", err
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultWorkloadLogConcurrency
+	}
+
+	if resourceType.Name != "" {
+		if labelSelector != "" {
+			return "", fmt.Errorf("a workload and a label selector cannot be used together")
+		}
+		selector, err := c.workloadPodSelector(ctx, resourceType, name, namespace)
+		if err != nil {
+			return "", err
+		}
+		labelSelector = selector
+	}
+	if labelSelector == "" {
+		return "", fmt.Errorf("either a workload or a label selector is required")
+	}
+
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", err)
+	}
+	if len(pods.Items) == 0 {
+		return "", fmt.Errorf("no pods found matching %s", labelSelector)
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+
+	selected := pods.Items
+	if len(selected) > maxPods {
+		selected = selected[:maxPods]
+	}
+
+	var streams []podLogStream
+	for i := range selected {
+		pod := &selected[i]
+		switch {
+		case opts.AllContainers:
+			for _, container := range podContainerNames(pod) {
+				streams = append(streams, podLogStream{pod: pod.Name, container: container})
+			}
+		case opts.Container != "":
+			streams = append(streams, podLogStream{pod: pod.Name, container: opts.Container})
+		default:
+			container, err := defaultContainer(pod)
+			if err != nil {
+				return "", err
+			}
+			streams = append(streams, podLogStream{pod: pod.Name, container: container})
+		}
+	}
+
+	logs := make([]string, len(streams))
+	errs := make([]error, len(streams))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, stream := range streams {
+		wg.Add(1)
+		go func(i int, stream podLogStream) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			logs[i], errs[i] = c.readPodLogs(ctx, namespace, stream.pod, streamOpts.podLogOptions(stream.container), filter)
+		}(i, stream)
+	}
+	wg.Wait()
+
+	var builder strings.Builder
+	var lines []timestampedLine
+	for i, stream := range streams {
+		if errs[i] != nil {
+			// A failing stream should not hide the logs of the other pods
+			fmt.Fprintf(&builder, "%s %v\n", stream.prefix(), errs[i])
+			continue
+		}
+		lines = append(lines, parseTimestampedLines(logs[i], stream.prefix())...)
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].timestamp.Before(lines[j].timestamp)
+	})
+	for _, line := range lines {
+		if opts.Timestamps && !line.timestamp.IsZero() {
+			fmt.Fprintf(&builder, "%s %s %s\n", line.prefix, line.timestamp.Format(time.RFC3339Nano), line.text)
+		} else {
+			fmt.Fprintf(&builder, "%s %s\n", line.prefix, line.text)
+		}
+	}
+
+	if len(pods.Items) > len(selected) {
+		fmt.Fprintf(&builder, "\nShowing logs of %d of %d pods matching %s\n", len(selected), len(pods.Items), labelSelector)
+	}
+
+	return builder.String(), nil
+}
+
+// workloadPodSelector returns the pod label selector of a Deployment, StatefulSet, DaemonSet, ReplicaSet or Job
+func (c *Client) workloadPodSelector(ctx context.Context, resourceType ResourceType, name, namespace string) (string, error) {
+	mapping, err := c.findResourceMapping(resourceType)
+	if err != nil {
+		return "", err
+	}
+	if !workloadSelectorKinds[mapping.GroupVersionKind.GroupKind()] {
+		return "", fmt.Errorf("%s is not supported, supported kinds: Deployment, StatefulSet, DaemonSet, ReplicaSet, Job", mapping.GroupVersionKind.GroupKind())
+	}
+
+	workload, err := c.GetResource(ctx, resourceType, name, namespace)
+	if err != nil {
+		return "", err
+	}
+
+	selectorMap, found, err := unstructured.NestedMap(workload, "spec", "selector")
+	if err != nil || !found {
+		return "", fmt.Errorf("%s %s has no pod selector", mapping.GroupVersionKind.Kind, name)
+	}
+	labelSelector := &metav1.LabelSelector{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(selectorMap, labelSelector); err != nil {
+		return "", fmt.Errorf("invalid pod selector of %s %s: %w", mapping.GroupVersionKind.Kind, name, err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return "", fmt.Errorf("invalid pod selector of %s %s: %w", mapping.GroupVersionKind.Kind, name, err)
+	}
+
+	return selector.String(), nil
+}
+
+// parseTimestampedLines splits logs read with timestamps into lines. Lines without a valid timestamp
+// inherit the timestamp of the line before them so they stay in place when interleaved.
+func parseTimestampedLines(logs, prefix string) []timestampedLine {
+	if logs == "" {
+		return nil
+	}
+
+	var lines []timestampedLine
+	var last time.Time
+	for _, text := range strings.Split(strings.TrimSuffix(logs, "\n"), "\n") {
+		line := timestampedLine{timestamp: last, prefix: prefix, text: text}
+		if ts, rest, ok := strings.Cut(text, " "); ok {
+			if timestamp, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+				line.timestamp = timestamp
+				line.text = rest
+				last = timestamp
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,142 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPodLogOptions(t *testing.T) {
+	sinceTime := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		opts             PodLogOptions
+		wantTailLines    *int64
+		wantSinceSeconds *int64
+		wantSinceTime    *time.Time
+		wantLimitBytes   *int64
+	}{
+		{
+			name:          "tail only",
+			opts:          PodLogOptions{TailLines: 50},
+			wantTailLines: int64Ptr(50),
+		},
+		{
+			name: "all lines",
+			opts: PodLogOptions{TailLines: -1},
+		},
+		{
+			name:             "since seconds",
+			opts:             PodLogOptions{TailLines: -1, SinceSeconds: 300},
+			wantSinceSeconds: int64Ptr(300),
+		},
+		{
+			name:          "since time",
+			opts:          PodLogOptions{TailLines: -1, SinceTime: &sinceTime},
+			wantSinceTime: &sinceTime,
+		},
+		{
+			name:           "limit bytes",
+			opts:           PodLogOptions{TailLines: 10, LimitBytes: 1024},
+			wantTailLines:  int64Ptr(10),
+			wantLimitBytes: int64Ptr(1024),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.opts.podLogOptions("app")
+			if got.Container != "app" {
+				t.Errorf("container = %q, want app", got.Container)
+			}
+			assertInt64Ptr(t, "tailLines", got.TailLines, tt.wantTailLines)
+			assertInt64Ptr(t, "sinceSeconds", got.SinceSeconds, tt.wantSinceSeconds)
+			assertInt64Ptr(t, "limitBytes", got.LimitBytes, tt.wantLimitBytes)
+			switch {
+			case tt.wantSinceTime == nil && got.SinceTime != nil:
+				t.Errorf("sinceTime = %v, want unset", got.SinceTime)
+			case tt.wantSinceTime != nil && (got.SinceTime == nil || !got.SinceTime.Time.Equal(*tt.wantSinceTime)):
+				t.Errorf("sinceTime = %v, want %v", got.SinceTime, tt.wantSinceTime)
+			}
+		})
+	}
+}
+
+// assertInt64Ptr reports an error if an optional API option differs from the wanted value
+func assertInt64Ptr(t *testing.T, name string, got, want *int64) {
+	t.Helper()
+	switch {
+	case want == nil && got != nil:
+		t.Errorf("%s = %d, want unset", name, *got)
+	case want != nil && got == nil:
+		t.Errorf("%s is unset, want %d", name, *want)
+	case want != nil && *got != *want:
+		t.Errorf("%s = %d, want %d", name, *got, *want)
+	}
+}
+
+func TestGetPodLogsRejectsSinceSecondsWithSinceTime(t *testing.T) {
+	sinceTime := time.Now()
+	client := &Client{}
+
+	_, err := client.GetPodLogs(context.Background(), DefaultNamespace, "web-0", PodLogOptions{SinceSeconds: 60, SinceTime: &sinceTime})
+	if err == nil || err.Error() != "since seconds and since time cannot be used together" {
+		t.Errorf("GetPodLogs error = %v, want since seconds and since time cannot be used together", err)
+	}
+}
+
+func TestParseTimestampedLines(t *testing.T) {
+	first := time.Date(2026, 1, 2, 15, 4, 5, 123456789, time.UTC)
+	second := time.Date(2026, 1, 2, 15, 4, 6, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		logs string
+		want []timestampedLine
+	}{
+		{
+			name: "empty",
+			logs: "",
+		},
+		{
+			name: "timestamped lines",
+			logs: "2026-01-02T15:04:05.123456789Z starting\n2026-01-02T15:04:06Z ready\n",
+			want: []timestampedLine{
+				{timestamp: first, prefix: "[web-0]", text: "starting"},
+				{timestamp: second, prefix: "[web-0]", text: "ready"},
+			},
+		},
+		{
+			name: "continuation lines inherit the previous timestamp",
+			logs: "2026-01-02T15:04:05.123456789Z panic: boom\n\tmain.go:12\n2026-01-02T15:04:06Z restarted",
+			want: []timestampedLine{
+				{timestamp: first, prefix: "[web-0]", text: "panic: boom"},
+				{timestamp: first, prefix: "[web-0]", text: "\tmain.go:12"},
+				{timestamp: second, prefix: "[web-0]", text: "restarted"},
+			},
+		},
+		{
+			name: "leading line without timestamp",
+			logs: "not a timestamp\n2026-01-02T15:04:06Z ready\n",
+			want: []timestampedLine{
+				{prefix: "[web-0]", text: "not a timestamp"},
+				{timestamp: second, prefix: "[web-0]", text: "ready"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseTimestampedLines(tt.logs, "[web-0]")
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseTimestampedLines returned %d lines, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if !got[i].timestamp.Equal(tt.want[i].timestamp) || got[i].prefix != tt.want[i].prefix || got[i].text != tt.want[i].text {
+					t.Errorf("line %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package k8s
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// ManifestResult reports the outcome of applying one document of a manifest stream
+type ManifestResult struct {
+	Index      int             `json:"index"`
+	APIVersion string          `json:"apiVersion,omitempty"`
+	Kind       string          `json:"kind,omitempty"`
+	Namespace  string          `json:"namespace,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	Status     string          `json:"status"`
+	Error      string          `json:"error,omitempty"`
+	Conflicts  []ApplyConflict `json:"conflicts,omitempty"`
+	// Object is the resource as the API server would persist it, only set for dry runs
+	Object map[string]interface{} `json:"object,omitempty"`
+}
+
+const (
+	// ManifestStatusApplied marks a document that was applied successfully
+	ManifestStatusApplied = "applied"
+	// ManifestStatusFailed marks a document that could not be applied
+	ManifestStatusFailed = "failed"
+)
+
+// parseManifest parses a single resource manifest in YAML or JSON format
+func parseManifest(manifest string) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	// JSON is a subset of YAML, so both formats are handled by the YAML parser
+	if err := yaml.Unmarshal([]byte(manifest), &obj.Object); err != nil {
+		return nil, fmt.Errorf("failed to parse resource manifest: %w", err)
+	}
+	if len(obj.Object) == 0 {
+		return nil, fmt.Errorf("failed to parse resource manifest: manifest is empty")
+	}
+
+	return obj, nil
+}
+
+// splitManifests splits a multi-document YAML stream into its non-empty documents
+func splitManifests(manifests string) ([]string, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(strings.NewReader(manifests)))
+
+	var documents []string
+	for {
+		document, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest document: %w", err)
+		}
+
+		if isEmptyDocument(document) {
+			continue
+		}
+		documents = append(documents, string(document))
+	}
+
+	return documents, nil
+}
+
+// isEmptyDocument reports whether a YAML document only contains whitespace, comments or separators
+func isEmptyDocument(document []byte) bool {
+	for _, line := range bytes.Split(document, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' || bytes.Equal(line, []byte("---")) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// ApplyManifests applies every document of a YAML or JSON manifest stream with server-side apply.
+// Each document's type is resolved from its own apiVersion and kind, and results are reported in order.
+func (c *Client) ApplyManifests(ctx context.Context, namespace, manifests, fieldManager string, force, dryRun bool) ([]ManifestResult, error) {
+	documents, err := splitManifests(manifests)
+	if err != nil {
+		return nil, err
+	}
+	if len(documents) == 0 {
+		return nil, fmt.Errorf("no resources found in manifests")
+	}
+
+	results := make([]ManifestResult, 0, len(documents))
+	for i, document := range documents {
+		result := ManifestResult{Index: i}
+
+		obj, err := parseManifest(document)
+		if err != nil {
+			result.Status = ManifestStatusFailed
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		result.APIVersion = obj.GetAPIVersion()
+		result.Kind = obj.GetKind()
+		result.Name = obj.GetName()
+		result.Namespace = obj.GetNamespace()
+
+		if result.APIVersion == "" || result.Kind == "" {
+			result.Status = ManifestStatusFailed
+			result.Error = "manifest must set apiVersion and kind"
+			results = append(results, result)
+			continue
+		}
+
+		resourceType := ResourceType{Name: obj.GetKind(), APIVersion: obj.GetAPIVersion()}
+		applied, err := c.applyObject(ctx, resourceType, namespace, obj, fieldManager, force, dryRun)
+		if err != nil {
+			result.Status = ManifestStatusFailed
+			result.Error = err.Error()
+			var conflictErr *ApplyConflictError
+			if errors.As(err, &conflictErr) {
+				result.Conflicts = conflictErr.Conflicts
+			}
+			results = append(results, result)
+			continue
+		}
+
+		result.Status = ManifestStatusApplied
+		result.Namespace = applied.GetNamespace()
+		if dryRun {
+			result.Object = applied.UnstructuredContent()
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,136 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// appliedObject records an apply request received by the fake dynamic client
+type appliedObject struct {
+	kind string
+	// namespace is the namespace of the request path, empty for cluster-scoped requests
+	namespace string
+	// objectNamespace is metadata.namespace of the applied object
+	objectNamespace string
+}
+
+// newFakeApplyClient returns a client that records apply requests and echoes the applied objects
+func newFakeApplyClient(t *testing.T) (*Client, *[]appliedObject) {
+	t.Helper()
+
+	client := newFakeDiscoveryClient(testAPIResources)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	applied := &[]appliedObject{}
+	dynamicClient.PrependReactor("patch", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patch := action.(clienttesting.PatchAction)
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(patch.GetPatch()); err != nil {
+			return true, nil, err
+		}
+		*applied = append(*applied, appliedObject{
+			kind:            obj.GetKind(),
+			namespace:       patch.GetNamespace(),
+			objectNamespace: obj.GetNamespace(),
+		})
+		return true, obj, nil
+	})
+	client.dynamicClient = dynamicClient
+
+	return client, applied
+}
+
+const mixedScopeManifests = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: team-a
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: reader
+  namespace: stray
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: team-a
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+  namespace: team-b
+`
+
+func TestApplyManifestsMixedScope(t *testing.T) {
+	client, applied := newFakeApplyClient(t)
+
+	results, err := client.ApplyManifests(context.Background(), "team-a", mixedScopeManifests, "", false, false)
+	if err != nil {
+		t.Fatalf("ApplyManifests returned error: %v", err)
+	}
+
+	wantStatus := []string{ManifestStatusApplied, ManifestStatusApplied, ManifestStatusApplied, ManifestStatusApplied, ManifestStatusFailed}
+	wantNamespace := []string{"", "", "team-a", "team-a", "team-b"}
+	if len(results) != len(wantStatus) {
+		t.Fatalf("got %d results, want %d", len(results), len(wantStatus))
+	}
+	for i, result := range results {
+		if result.Status != wantStatus[i] {
+			t.Errorf("result %d (%s %s) status = %s (%s), want %s", i, result.Kind, result.Name, result.Status, result.Error, wantStatus[i])
+		}
+		if result.Namespace != wantNamespace[i] {
+			t.Errorf("result %d (%s %s) namespace = %q, want %q", i, result.Kind, result.Name, result.Namespace, wantNamespace[i])
+		}
+	}
+	if !strings.Contains(results[4].Error, "does not match the namespace parameter") {
+		t.Errorf("result 4 error = %q, want a namespace mismatch", results[4].Error)
+	}
+
+	want := []appliedObject{
+		{kind: "Namespace"},
+		{kind: "ClusterRole"},
+		{kind: "Deployment", namespace: "team-a", objectNamespace: "team-a"},
+		{kind: "Deployment", namespace: "team-a", objectNamespace: "team-a"},
+	}
+	if len(*applied) != len(want) {
+		t.Fatalf("got %d apply requests %v, want %d", len(*applied), *applied, len(want))
+	}
+	for i, got := range *applied {
+		if got != want[i] {
+			t.Errorf("apply request %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestApplyManifestsWithoutNamespace(t *testing.T) {
+	client, applied := newFakeApplyClient(t)
+
+	results, err := client.ApplyManifests(context.Background(), "", mixedScopeManifests, "", false, false)
+	if err != nil {
+		t.Fatalf("ApplyManifests returned error: %v", err)
+	}
+
+	// Namespace-scoped documents without metadata.namespace need the namespace parameter
+	wantStatus := []string{ManifestStatusApplied, ManifestStatusApplied, ManifestStatusFailed, ManifestStatusApplied, ManifestStatusApplied}
+	for i, result := range results {
+		if result.Status != wantStatus[i] {
+			t.Errorf("result %d (%s %s) status = %s (%s), want %s", i, result.Kind, result.Name, result.Status, result.Error, wantStatus[i])
+		}
+	}
+	if len(*applied) != 4 {
+		t.Errorf("got %d apply requests, want 4", len(*applied))
+	}
+}
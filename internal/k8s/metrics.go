@@ -0,0 +1,400 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// DefaultTopLimit is the default number of nodes or pods returned by TopNodes and TopPods
+	DefaultTopLimit = 20
+
+	// TopSortCPU sorts by CPU usage
+	TopSortCPU = "cpu"
+	// TopSortMemory sorts by memory usage
+	TopSortMemory = "memory"
+	// TopSortCPUPercent sorts by CPU usage relative to node allocatable or pod requests
+	TopSortCPUPercent = "cpu_percent"
+	// TopSortMemoryPercent sorts by memory usage relative to node allocatable or pod requests
+	TopSortMemoryPercent = "memory_percent"
+	// TopSortName sorts by namespace and name
+	TopSortName = "name"
+)
+
+var (
+	nodeMetricsResource = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "nodes"}
+	podMetricsResource  = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+)
+
+// ResourceUsage is the usage of one compute resource, CPU in millicores and memory in Mi
+type ResourceUsage struct {
+	Usage       string `json:"usage"`
+	Request     string `json:"request,omitempty"`
+	Limit       string `json:"limit,omitempty"`
+	Allocatable string `json:"allocatable,omitempty"`
+	// Percent is the usage relative to the node allocatable for nodes and to the requests for pods
+	Percent *float64 `json:"percent,omitempty"`
+	// LimitPercent is the usage relative to the limits of a pod
+	LimitPercent *float64 `json:"limitPercent,omitempty"`
+
+	// usage is the usage in millicores for CPU and bytes for memory, used for sorting
+	usage int64
+}
+
+// NodeUsage is the resource usage of a node
+type NodeUsage struct {
+	Name      string        `json:"name"`
+	CPU       ResourceUsage `json:"cpu"`
+	Memory    ResourceUsage `json:"memory"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+// PodUsage is the resource usage of a pod
+type PodUsage struct {
+	Namespace  string           `json:"namespace"`
+	Name       string           `json:"name"`
+	Node       string           `json:"node,omitempty"`
+	CPU        ResourceUsage    `json:"cpu"`
+	Memory     ResourceUsage    `json:"memory"`
+	Containers []ContainerUsage `json:"containers,omitempty"`
+	Timestamp  time.Time        `json:"timestamp"`
+}
+
+// ContainerUsage is the resource usage of a container of a pod
+type ContainerUsage struct {
+	Name   string        `json:"name"`
+	CPU    ResourceUsage `json:"cpu"`
+	Memory ResourceUsage `json:"memory"`
+}
+
+// TopNodesResult is the result of TopNodes
+type TopNodesResult struct {
+	Nodes []NodeUsage `json:"nodes"`
+	// Total is the number of nodes with metrics before the limit was applied
+	Total int `json:"total"`
+}
+
+// TopPodsResult is the result of TopPods
+type TopPodsResult struct {
+	Pods []PodUsage `json:"pods"`
+	// Total is the number of pods with metrics before the limit was applied
+	Total int `json:"total"`
+}
+
+// TopNodes returns the CPU and memory usage of nodes from the metrics API, with the usage relative
+// to the node allocatable, sorted by sortBy in descending order and limited to limit nodes (0 for all)
+func (c *Client) TopNodes(ctx context.Context, labelSelector, sortBy string, limit int) (*TopNodesResult, error) {
+	if err := validateTopSort(sortBy); err != nil {
+		return nil, err
+	}
+
+	options := metav1.ListOptions{LabelSelector: labelSelector}
+	metrics, err := c.dynamicClient.Resource(nodeMetricsResource).List(ctx, options)
+	if err != nil {
+		return nil, metricsError(err)
+	}
+
+	nodes, err := c.clientset.CoreV1().Nodes().List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	allocatable := make(map[string]corev1.ResourceList, len(nodes.Items))
+	for _, node := range nodes.Items {
+		allocatable[node.Name] = node.Status.Allocatable
+	}
+
+	result := &TopNodesResult{Nodes: []NodeUsage{}}
+	for _, item := range metrics.Items {
+		usage, err := metricsUsage(item.Object, "usage")
+		if err != nil {
+			return nil, fmt.Errorf("invalid metrics of node %s: %w", item.GetName(), err)
+		}
+		nodeAllocatable, ok := allocatable[item.GetName()]
+		if !ok {
+			// The node was deleted since its metrics were collected
+			continue
+		}
+
+		cpu := cpuUsage(usage)
+		if quantity, ok := nodeAllocatable[corev1.ResourceCPU]; ok {
+			cpu.Allocatable = formatCPU(quantity.MilliValue())
+			cpu.Percent = usagePercent(cpu.usage, quantity.MilliValue())
+		}
+		memory := memoryUsage(usage)
+		if quantity, ok := nodeAllocatable[corev1.ResourceMemory]; ok {
+			memory.Allocatable = formatMemory(quantity.Value())
+			memory.Percent = usagePercent(memory.usage, quantity.Value())
+		}
+
+		result.Nodes = append(result.Nodes, NodeUsage{
+			Name:      item.GetName(),
+			CPU:       cpu,
+			Memory:    memory,
+			Timestamp: metricsTimestamp(item.Object),
+		})
+	}
+	result.Total = len(result.Nodes)
+
+	sort.SliceStable(result.Nodes, func(i, j int) bool {
+		a, b := result.Nodes[i], result.Nodes[j]
+		return topLess(sortBy, a.Name, b.Name, a.CPU, b.CPU, a.Memory, b.Memory)
+	})
+	if limit > 0 && len(result.Nodes) > limit {
+		result.Nodes = result.Nodes[:limit]
+	}
+
+	return result, nil
+}
+
+// TopPods returns the CPU and memory usage of pods from the metrics API, with the usage relative
+// to the requests and limits of their containers, sorted by sortBy in descending order and limited
+// to limit pods (0 for all). An empty namespace returns pods of all namespaces.
+func (c *Client) TopPods(ctx context.Context, namespace, labelSelector, sortBy string, limit int, containers bool) (*TopPodsResult, error) {
+	if err := validateTopSort(sortBy); err != nil {
+		return nil, err
+	}
+
+	options := metav1.ListOptions{LabelSelector: labelSelector}
+	metrics, err := c.dynamicClient.Resource(podMetricsResource).Namespace(namespace).List(ctx, options)
+	if err != nil {
+		return nil, metricsError(err)
+	}
+
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	podsByName := make(map[string]*corev1.Pod, len(pods.Items))
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		podsByName[pod.Namespace+"/"+pod.Name] = pod
+	}
+
+	result := &TopPodsResult{Pods: []PodUsage{}}
+	for _, item := range metrics.Items {
+		pod, ok := podsByName[item.GetNamespace()+"/"+item.GetName()]
+		if !ok {
+			// The pod was deleted since its metrics were collected
+			continue
+		}
+		specs := make(map[string]corev1.ResourceRequirements, len(pod.Spec.Containers))
+		for _, container := range pod.Spec.Containers {
+			specs[container.Name] = container.Resources
+		}
+
+		containerMetrics, _, err := unstructured.NestedSlice(item.Object, "containers")
+		if err != nil {
+			return nil, fmt.Errorf("invalid metrics of pod %s/%s: %w", item.GetNamespace(), item.GetName(), err)
+		}
+
+		usage := PodUsage{
+			Namespace: item.GetNamespace(),
+			Name:      item.GetName(),
+			Node:      pod.Spec.NodeName,
+			Timestamp: metricsTimestamp(item.Object),
+		}
+		var podCPU, podMemory containerTotals
+		for _, entry := range containerMetrics {
+			containerObject, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(containerObject, "name")
+			containerUsage, err := metricsUsage(containerObject, "usage")
+			if err != nil {
+				return nil, fmt.Errorf("invalid metrics of pod %s/%s: %w", item.GetNamespace(), item.GetName(), err)
+			}
+
+			resources := specs[name]
+			cpu := containerTotals{
+				usage:   cpuQuantity(containerUsage),
+				request: cpuQuantity(resources.Requests),
+				limit:   cpuQuantity(resources.Limits),
+			}
+			memory := containerTotals{
+				usage:   memoryQuantity(containerUsage),
+				request: memoryQuantity(resources.Requests),
+				limit:   memoryQuantity(resources.Limits),
+			}
+			podCPU.add(cpu)
+			podMemory.add(memory)
+
+			if containers {
+				usage.Containers = append(usage.Containers, ContainerUsage{
+					Name:   name,
+					CPU:    cpu.resourceUsage(formatCPU),
+					Memory: memory.resourceUsage(formatMemory),
+				})
+			}
+		}
+		usage.CPU = podCPU.resourceUsage(formatCPU)
+		usage.Memory = podMemory.resourceUsage(formatMemory)
+
+		result.Pods = append(result.Pods, usage)
+	}
+	result.Total = len(result.Pods)
+
+	sort.SliceStable(result.Pods, func(i, j int) bool {
+		a, b := result.Pods[i], result.Pods[j]
+		return topLess(sortBy, a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name, a.CPU, b.CPU, a.Memory, b.Memory)
+	})
+	if limit > 0 && len(result.Pods) > limit {
+		result.Pods = result.Pods[:limit]
+	}
+
+	return result, nil
+}
+
+// containerTotals accumulates the usage, requests and limits of one resource of containers.
+// A limit of -1 means at least one container has no limit.
+type containerTotals struct {
+	usage   int64
+	request int64
+	limit   int64
+}
+
+// add adds the totals of a container
+func (t *containerTotals) add(container containerTotals) {
+	t.usage += container.usage
+	t.request += container.request
+	if t.limit >= 0 && container.limit > 0 {
+		t.limit += container.limit
+	} else {
+		t.limit = -1
+	}
+}
+
+// resourceUsage returns the usage with percentages of the requests and limits
+func (t containerTotals) resourceUsage(format func(int64) string) ResourceUsage {
+	usage := ResourceUsage{Usage: format(t.usage), usage: t.usage}
+	if t.request > 0 {
+		usage.Request = format(t.request)
+		usage.Percent = usagePercent(t.usage, t.request)
+	}
+	if t.limit > 0 {
+		usage.Limit = format(t.limit)
+		usage.LimitPercent = usagePercent(t.usage, t.limit)
+	}
+	return usage
+}
+
+// validateTopSort checks a sort key of TopNodes and TopPods
+func validateTopSort(sortBy string) error {
+	switch sortBy {
+	case TopSortCPU, TopSortMemory, TopSortCPUPercent, TopSortMemoryPercent, TopSortName:
+		return nil
+	default:
+		return fmt.Errorf("unsupported sort key: %s, must be one of %s, %s, %s, %s or %s",
+			sortBy, TopSortCPU, TopSortMemory, TopSortCPUPercent, TopSortMemoryPercent, TopSortName)
+	}
+}
+
+// topLess orders usages by the sort key, highest usage first and by name for equal usage
+func topLess(sortBy, nameA, nameB string, cpuA, cpuB, memoryA, memoryB ResourceUsage) bool {
+	var a, b float64
+	switch sortBy {
+	case TopSortCPU:
+		a, b = float64(cpuA.usage), float64(cpuB.usage)
+	case TopSortMemory:
+		a, b = float64(memoryA.usage), float64(memoryB.usage)
+	case TopSortCPUPercent:
+		a, b = percentValue(cpuA.Percent), percentValue(cpuB.Percent)
+	case TopSortMemoryPercent:
+		a, b = percentValue(memoryA.Percent), percentValue(memoryB.Percent)
+	}
+	if a != b {
+		return a > b
+	}
+	return nameA < nameB
+}
+
+// percentValue returns a percentage for sorting, usages without a percentage sort last
+func percentValue(percent *float64) float64 {
+	if percent == nil {
+		return -1
+	}
+	return *percent
+}
+
+// metricsError explains a failed metrics API request
+func metricsError(err error) error {
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("metrics API %s is not available, is metrics-server installed: %w", nodeMetricsResource.GroupVersion(), err)
+	}
+	return fmt.Errorf("failed to get metrics: %w", err)
+}
+
+// metricsUsage parses the usage field of node, pod or container metrics
+func metricsUsage(obj map[string]interface{}, field string) (corev1.ResourceList, error) {
+	values, _, err := unstructured.NestedStringMap(obj, field)
+	if err != nil {
+		return nil, err
+	}
+	usage := make(corev1.ResourceList, len(values))
+	for name, value := range values {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s usage %q: %w", name, value, err)
+		}
+		usage[corev1.ResourceName(name)] = quantity
+	}
+	return usage, nil
+}
+
+// metricsTimestamp returns the time metrics were collected
+func metricsTimestamp(obj map[string]interface{}) time.Time {
+	value, _, _ := unstructured.NestedString(obj, "timestamp")
+	timestamp, _ := time.Parse(time.RFC3339, value)
+	return timestamp
+}
+
+// cpuUsage returns the CPU usage of node metrics
+func cpuUsage(usage corev1.ResourceList) ResourceUsage {
+	value := cpuQuantity(usage)
+	return ResourceUsage{Usage: formatCPU(value), usage: value}
+}
+
+// memoryUsage returns the memory usage of node metrics
+func memoryUsage(usage corev1.ResourceList) ResourceUsage {
+	value := memoryQuantity(usage)
+	return ResourceUsage{Usage: formatMemory(value), usage: value}
+}
+
+// cpuQuantity returns the CPU of a resource list in millicores
+func cpuQuantity(resources corev1.ResourceList) int64 {
+	return resources.Cpu().MilliValue()
+}
+
+// memoryQuantity returns the memory of a resource list in bytes
+func memoryQuantity(resources corev1.ResourceList) int64 {
+	return resources.Memory().Value()
+}
+
+// formatCPU formats millicores like kubectl top
+func formatCPU(millicores int64) string {
+	return fmt.Sprintf("%dm", millicores)
+}
+
+// formatMemory formats bytes in Mi like kubectl top
+func formatMemory(bytes int64) string {
+	return fmt.Sprintf("%dMi", bytes/(1024*1024))
+}
+
+// usagePercent returns usage as a percentage of total, rounded to one decimal
+func usagePercent(usage, total int64) *float64 {
+	if total <= 0 {
+		return nil
+	}
+	percent := math.Round(float64(usage)/float64(total)*1000) / 10
+	return &percent
+}
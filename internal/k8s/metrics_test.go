@@ -0,0 +1,175 @@
+package k8s
+
+import (
+	"sort"
+	"testing"
+)
+
+// percentString formats an optional percentage for test messages
+func percentString(percent *float64) interface{} {
+	if percent == nil {
+		return "<nil>"
+	}
+	return *percent
+}
+
+func TestContainerTotalsResourceUsage(t *testing.T) {
+	tests := []struct {
+		name             string
+		containers       []containerTotals
+		want             ResourceUsage
+		wantPercent      *float64
+		wantLimitPercent *float64
+	}{
+		{
+			name:       "requests and limits",
+			containers: []containerTotals{{usage: 100, request: 200, limit: 400}, {usage: 50, request: 100, limit: 200}},
+			want:       ResourceUsage{Usage: "150m", Request: "300m", Limit: "600m"},
+			// 150 of 300 and 600
+			wantPercent:      floatPtr(50),
+			wantLimitPercent: floatPtr(25),
+		},
+		{
+			name:        "a container without a limit leaves the pod unlimited",
+			containers:  []containerTotals{{usage: 100, request: 200, limit: 400}, {usage: 50, request: 100}, {usage: 10, request: 10, limit: 100}},
+			want:        ResourceUsage{Usage: "160m", Request: "310m"},
+			wantPercent: floatPtr(51.6),
+		},
+		{
+			name:       "no requests",
+			containers: []containerTotals{{usage: 100, limit: 300}},
+			want:       ResourceUsage{Usage: "100m", Limit: "300m"},
+			// 100 of 300 rounded to one decimal
+			wantLimitPercent: floatPtr(33.3),
+		},
+		{
+			name:        "usage above the request",
+			containers:  []containerTotals{{usage: 250, request: 100}},
+			want:        ResourceUsage{Usage: "250m", Request: "100m"},
+			wantPercent: floatPtr(250),
+		},
+		{
+			name: "no containers",
+			want: ResourceUsage{Usage: "0m"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var totals containerTotals
+			for _, container := range tt.containers {
+				totals.add(container)
+			}
+
+			got := totals.resourceUsage(formatCPU)
+			if got.Usage != tt.want.Usage || got.Request != tt.want.Request || got.Limit != tt.want.Limit {
+				t.Errorf("resourceUsage = usage %q, request %q, limit %q, want usage %q, request %q, limit %q",
+					got.Usage, got.Request, got.Limit, tt.want.Usage, tt.want.Request, tt.want.Limit)
+			}
+			if !equalPercent(got.Percent, tt.wantPercent) {
+				t.Errorf("percent = %v, want %v", percentString(got.Percent), percentString(tt.wantPercent))
+			}
+			if !equalPercent(got.LimitPercent, tt.wantLimitPercent) {
+				t.Errorf("limit percent = %v, want %v", percentString(got.LimitPercent), percentString(tt.wantLimitPercent))
+			}
+		})
+	}
+}
+
+func TestUsagePercent(t *testing.T) {
+	tests := []struct {
+		usage, total int64
+		want         *float64
+	}{
+		{usage: 1, total: 3, want: floatPtr(33.3)},
+		{usage: 2, total: 3, want: floatPtr(66.7)},
+		{usage: 0, total: 10, want: floatPtr(0)},
+		{usage: 5, total: 0},
+		{usage: 5, total: -1},
+	}
+
+	for _, tt := range tests {
+		if got := usagePercent(tt.usage, tt.total); !equalPercent(got, tt.want) {
+			t.Errorf("usagePercent(%d, %d) = %v, want %v", tt.usage, tt.total, percentString(got), percentString(tt.want))
+		}
+	}
+}
+
+func TestFormatMemory(t *testing.T) {
+	if got := formatMemory(1536 * 1024 * 1024); got != "1536Mi" {
+		t.Errorf("formatMemory = %q, want 1536Mi", got)
+	}
+	if got := formatMemory(1024*1024 - 1); got != "0Mi" {
+		t.Errorf("formatMemory = %q, want 0Mi", got)
+	}
+}
+
+func TestTopLess(t *testing.T) {
+	type entry struct {
+		name        string
+		cpu, memory ResourceUsage
+	}
+	entries := []entry{
+		{name: "b", cpu: ResourceUsage{usage: 100, Percent: floatPtr(10)}, memory: ResourceUsage{usage: 300}},
+		{name: "c", cpu: ResourceUsage{usage: 300}, memory: ResourceUsage{usage: 100, Percent: floatPtr(90)}},
+		{name: "a", cpu: ResourceUsage{usage: 100, Percent: floatPtr(50)}, memory: ResourceUsage{usage: 200, Percent: floatPtr(5)}},
+	}
+
+	tests := []struct {
+		sortBy string
+		want   []string
+	}{
+		{sortBy: TopSortCPU, want: []string{"c", "a", "b"}},
+		{sortBy: TopSortMemory, want: []string{"b", "a", "c"}},
+		// Usages without a percentage sort last
+		{sortBy: TopSortCPUPercent, want: []string{"a", "b", "c"}},
+		{sortBy: TopSortMemoryPercent, want: []string{"c", "a", "b"}},
+		{sortBy: TopSortName, want: []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			sorted := append([]entry(nil), entries...)
+			sort.Slice(sorted, func(i, j int) bool {
+				return topLess(tt.sortBy, sorted[i].name, sorted[j].name, sorted[i].cpu, sorted[j].cpu, sorted[i].memory, sorted[j].memory)
+			})
+
+			var got []string
+			for _, e := range sorted {
+				got = append(got, e.name)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("sorted = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("sorted = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateTopSort(t *testing.T) {
+	for _, sortBy := range []string{TopSortCPU, TopSortMemory, TopSortCPUPercent, TopSortMemoryPercent, TopSortName} {
+		if err := validateTopSort(sortBy); err != nil {
+			t.Errorf("validateTopSort(%q) returned error: %v", sortBy, err)
+		}
+	}
+	if err := validateTopSort("disk"); err == nil {
+		t.Errorf("validateTopSort(disk) returned no error")
+	}
+}
+
+// floatPtr returns a pointer to a percentage
+func floatPtr(value float64) *float64 {
+	return &value
+}
+
+// equalPercent reports whether two optional percentages are equal
+func equalPercent(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,468 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+const (
+	// DefaultPortForwardIdleTimeout is the default time a port-forward without connections is kept open
+	DefaultPortForwardIdleTimeout = 10 * time.Minute
+	// DefaultPortForwardAddress is the default local address port-forwards listen on
+	DefaultPortForwardAddress = "127.0.0.1"
+	// MaxPortForwardSessions is the number of port-forwards that can be open at the same time
+	MaxPortForwardSessions = 16
+	// portForwardReadyTimeout is how long to wait for a port-forward to be established
+	portForwardReadyTimeout = 30 * time.Second
+)
+
+// PortForwardSession describes an open port-forward from the server host to a pod
+type PortForwardSession struct {
+	ID        string `json:"id"`
+	Namespace string `json:"namespace"`
+	// Target is the pod or service the port-forward was started for, e.g. service/web
+	Target            string    `json:"target"`
+	Pod               string    `json:"pod"`
+	LocalAddress      string    `json:"localAddress"`
+	LocalPort         int       `json:"localPort"`
+	RemotePort        int       `json:"remotePort"`
+	StartedAt         time.Time `json:"startedAt"`
+	LastActive        time.Time `json:"lastActive"`
+	ActiveConnections int       `json:"activeConnections"`
+	TotalConnections  int       `json:"totalConnections"`
+	IdleTimeout       string    `json:"idleTimeout"`
+}
+
+// portForwardSession is an open port-forward. Connections accepted on the local listener are
+// proxied to a loopback port of the client-go port forwarder, which lets the session track
+// activity and close itself when idle.
+type portForwardSession struct {
+	mu          sync.Mutex
+	info        PortForwardSession
+	idleTimeout time.Duration
+	listener    net.Listener
+	// forwardAddress is the loopback address of the client-go port forwarder
+	forwardAddress string
+	stopCh         chan struct{}
+	stopOnce       sync.Once
+}
+
+// portForwardRegistry holds the open port-forwards of a client
+type portForwardRegistry struct {
+	mu       sync.Mutex
+	sessions map[string]*portForwardSession
+	// reserved counts port-forwards that are being started and are not in sessions yet
+	reserved int
+	nextID   int
+}
+
+// newPortForwardRegistry creates an empty port-forward registry
+func newPortForwardRegistry() *portForwardRegistry {
+	return &portForwardRegistry{sessions: map[string]*portForwardSession{}}
+}
+
+// reserve claims a slot for a port-forward that is being started
+func (r *portForwardRegistry) reserve() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if open := len(r.sessions) + r.reserved; open >= MaxPortForwardSessions {
+		return fmt.Errorf("too many open port-forwards (%d), stop one first", open)
+	}
+	r.reserved++
+	return nil
+}
+
+// release gives back a slot claimed by reserve for a port-forward that failed to start
+func (r *portForwardRegistry) release() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reserved--
+}
+
+// add registers a started port-forward in the slot claimed by reserve and assigns its ID
+func (r *portForwardRegistry) add(session *portForwardSession) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reserved--
+	r.nextID++
+	session.info.ID = fmt.Sprintf("pf-%d", r.nextID)
+	r.sessions[session.info.ID] = session
+}
+
+// StartPortForward opens a port-forward from localAddress:localPort on the server host to a port of
+// a pod or service. A service is resolved to a ready pod backing it, and its port to the matching
+// target port of that pod. A localPort of 0 picks a free port. The session is closed after it had
+// no connections for idleTimeout, or when StopPortForward is called.
+func (c *Client) StartPortForward(ctx context.Context, namespace, kind, name string, remotePort int, localAddress string, localPort int, idleTimeout time.Duration) (*PortForwardSession, error) {
+	if remotePort <= 0 || remotePort > 65535 {
+		return nil, fmt.Errorf("invalid remote port %d", remotePort)
+	}
+	if localPort < 0 || localPort > 65535 {
+		return nil, fmt.Errorf("invalid local port %d", localPort)
+	}
+	if localAddress == "" {
+		localAddress = DefaultPortForwardAddress
+	}
+	if !isLoopbackAddress(localAddress) {
+		// Any other interface would expose the pod to the network without authentication
+		return nil, fmt.Errorf("local address %s is not a loopback address, port-forwards only listen on the server host", localAddress)
+	}
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultPortForwardIdleTimeout
+	}
+
+	if err := c.portForwards.reserve(); err != nil {
+		return nil, err
+	}
+	registered := false
+	defer func() {
+		if !registered {
+			c.portForwards.release()
+		}
+	}()
+
+	var pod *corev1.Pod
+	podPort := remotePort
+	var target string
+	switch strings.ToLower(kind) {
+	case "", "pod", "pods", "po":
+		var err error
+		pod, err = c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod: %w", err)
+		}
+		if pod.Status.Phase != corev1.PodRunning {
+			return nil, fmt.Errorf("pod %s is not running, phase: %s", name, pod.Status.Phase)
+		}
+		target = "pod/" + name
+	case "service", "services", "svc":
+		var err error
+		pod, podPort, err = c.resolveServicePod(ctx, namespace, name, remotePort)
+		if err != nil {
+			return nil, err
+		}
+		target = "service/" + name
+	default:
+		return nil, fmt.Errorf("unsupported port-forward target kind %q, must be pod or service", kind)
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(localAddress, strconv.Itoa(localPort)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s:%d: %w", localAddress, localPort, err)
+	}
+
+	now := time.Now().UTC()
+	session := &portForwardSession{
+		info: PortForwardSession{
+			Namespace:    namespace,
+			Target:       target,
+			Pod:          pod.Name,
+			LocalAddress: localAddress,
+			LocalPort:    listener.Addr().(*net.TCPAddr).Port,
+			RemotePort:   podPort,
+			StartedAt:    now,
+			LastActive:   now,
+			IdleTimeout:  idleTimeout.String(),
+		},
+		idleTimeout: idleTimeout,
+		listener:    listener,
+		stopCh:      make(chan struct{}),
+	}
+
+	c.portForwards.add(session)
+	registered = true
+
+	if err := c.startForwarder(session, namespace, pod.Name, podPort); err != nil {
+		c.closePortForward(session)
+		return nil, err
+	}
+
+	go c.acceptPortForwardConnections(session)
+	go c.reapIdlePortForward(session)
+
+	info := session.snapshot()
+	return &info, nil
+}
+
+// ListPortForwards returns the open port-forwards ordered by start time
+func (c *Client) ListPortForwards() []PortForwardSession {
+	c.portForwards.mu.Lock()
+	sessions := make([]PortForwardSession, 0, len(c.portForwards.sessions))
+	for _, session := range c.portForwards.sessions {
+		sessions = append(sessions, session.snapshot())
+	}
+	c.portForwards.mu.Unlock()
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].StartedAt.Before(sessions[j].StartedAt)
+	})
+	return sessions
+}
+
+// StopPortForward closes an open port-forward
+func (c *Client) StopPortForward(id string) (*PortForwardSession, error) {
+	c.portForwards.mu.Lock()
+	session, ok := c.portForwards.sessions[id]
+	c.portForwards.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("port-forward %s not found", id)
+	}
+
+	c.closePortForward(session)
+	info := session.snapshot()
+	return &info, nil
+}
+
+// isLoopbackAddress reports whether a listen address only accepts connections from the local host
+func isLoopbackAddress(address string) bool {
+	if strings.EqualFold(address, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(address)
+	return ip != nil && ip.IsLoopback()
+}
+
+// resolveServicePod picks a running and ready pod selected by a service and translates the service
+// port to the port of that pod, the same way kubectl port-forward does
+func (c *Client) resolveServicePod(ctx context.Context, namespace, name string, servicePort int) (*corev1.Pod, int, error) {
+	service, err := c.clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get service: %w", err)
+	}
+	if len(service.Spec.Selector) == 0 {
+		return nil, 0, fmt.Errorf("service %s has no selector", name)
+	}
+
+	var port *corev1.ServicePort
+	for i := range service.Spec.Ports {
+		if int(service.Spec.Ports[i].Port) == servicePort {
+			port = &service.Spec.Ports[i]
+			break
+		}
+	}
+	if port == nil {
+		return nil, 0, fmt.Errorf("service %s does not expose port %d", name, servicePort)
+	}
+
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(service.Spec.Selector).String(),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning || !isPodReady(pod) {
+			continue
+		}
+		podPort, err := servicePortTarget(port, pod)
+		if err != nil {
+			return nil, 0, err
+		}
+		return pod, podPort, nil
+	}
+
+	return nil, 0, fmt.Errorf("no ready pod found for service %s", name)
+}
+
+// servicePortTarget returns the pod port a service port routes to
+func servicePortTarget(port *corev1.ServicePort, pod *corev1.Pod) (int, error) {
+	switch {
+	case port.TargetPort.Type == intstr.String:
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				if containerPort.Name == port.TargetPort.StrVal {
+					return int(containerPort.ContainerPort), nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("pod %s has no port named %s", pod.Name, port.TargetPort.StrVal)
+	case port.TargetPort.IntValue() > 0:
+		return port.TargetPort.IntValue(), nil
+	default:
+		// An unset target port defaults to the service port
+		return int(port.Port), nil
+	}
+}
+
+// isPodReady reports whether a pod has the Ready condition
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// startForwarder starts a client-go port forwarder from a random loopback port to the pod port
+func (c *Client) startForwarder(session *portForwardSession, namespace, podName string, podPort int) error {
+	transport, upgrader, err := spdy.RoundTripperFor(c.restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create port-forward transport: %w", err)
+	}
+	url := c.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	readyCh := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{fmt.Sprintf("0:%d", podPort)}, session.stopCh, readyCh, io.Discard, io.Discard)
+	if err != nil {
+		return fmt.Errorf("failed to create port-forward: %w", err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- forwarder.ForwardPorts()
+	}()
+
+	select {
+	case <-readyCh:
+	case err := <-errCh:
+		return fmt.Errorf("failed to start port-forward: %w", err)
+	case <-time.After(portForwardReadyTimeout):
+		return fmt.Errorf("timed out starting port-forward to pod %s", podName)
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		return fmt.Errorf("failed to get port-forward ports: %w", err)
+	}
+	if len(ports) == 0 {
+		return fmt.Errorf("port-forward to pod %s has no ports", podName)
+	}
+	session.forwardAddress = net.JoinHostPort("127.0.0.1", strconv.Itoa(int(ports[0].Local)))
+
+	// The forwarder stops when the pod goes away, which ends the session too
+	go func() {
+		if err := <-errCh; err != nil {
+			log.Printf("port-forward to pod %s/%s stopped: %v", namespace, podName, err)
+		}
+		c.closePortForward(session)
+	}()
+
+	return nil
+}
+
+// acceptPortForwardConnections proxies connections accepted on the session listener to the forwarder
+func (c *Client) acceptPortForwardConnections(session *portForwardSession) {
+	for {
+		conn, err := session.listener.Accept()
+		if err != nil {
+			// The listener is closed when the session stops
+			return
+		}
+		go session.proxy(conn)
+	}
+}
+
+// proxy copies data between a local connection and the port forwarder until either side closes
+func (s *portForwardSession) proxy(conn net.Conn) {
+	defer conn.Close()
+
+	upstream, err := net.Dial("tcp", s.forwardAddress)
+	if err != nil {
+		log.Printf("port-forward %s: failed to connect to forwarder: %v", s.info.ID, err)
+		return
+	}
+	defer upstream.Close()
+
+	s.mu.Lock()
+	s.info.ActiveConnections++
+	s.info.TotalConnections++
+	s.info.LastActive = time.Now().UTC()
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.info.ActiveConnections--
+		s.info.LastActive = time.Now().UTC()
+		s.mu.Unlock()
+	}()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(upstream, conn)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(conn, upstream)
+		done <- struct{}{}
+	}()
+
+	select {
+	case <-done:
+	case <-s.stopCh:
+	}
+}
+
+// reapIdlePortForward closes a session once it had no connections for its idle timeout
+func (c *Client) reapIdlePortForward(session *portForwardSession) {
+	interval := session.idleTimeout / 4
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-session.stopCh:
+			return
+		case <-ticker.C:
+			session.mu.Lock()
+			idle := session.info.ActiveConnections == 0 && time.Since(session.info.LastActive) >= session.idleTimeout
+			session.mu.Unlock()
+			if idle {
+				c.closePortForward(session)
+				return
+			}
+		}
+	}
+}
+
+// closePortForward stops a session and removes it from the registry
+func (c *Client) closePortForward(session *portForwardSession) {
+	session.stopOnce.Do(func() {
+		close(session.stopCh)
+		_ = session.listener.Close()
+	})
+
+	// The ID is set before the session is started and never changes
+	id := session.info.ID
+	c.portForwards.mu.Lock()
+	if current, ok := c.portForwards.sessions[id]; ok && current == session {
+		delete(c.portForwards.sessions, id)
+	}
+	c.portForwards.mu.Unlock()
+}
+
+// snapshot returns a copy of the session information
+func (s *portForwardSession) snapshot() PortForwardSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.info
+}
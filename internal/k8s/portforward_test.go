@@ -0,0 +1,43 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestIsLoopbackAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{"127.0.0.1", true},
+		{"127.1.2.3", true},
+		{"::1", true},
+		{"localhost", true},
+		{"LOCALHOST", true},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"192.168.1.10", false},
+		{"example.com", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isLoopbackAddress(tt.address); got != tt.want {
+			t.Errorf("isLoopbackAddress(%q) = %v, want %v", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestStartPortForwardRejectsNonLoopbackAddress(t *testing.T) {
+	client := &Client{portForwards: newPortForwardRegistry()}
+
+	_, err := client.StartPortForward(context.Background(), DefaultNamespace, "pod", "web", 8080, "0.0.0.0", 0, 0)
+	if err == nil || !strings.Contains(err.Error(), "not a loopback address") {
+		t.Fatalf("StartPortForward on 0.0.0.0 error = %v, want a loopback error", err)
+	}
+	if open := len(client.ListPortForwards()); open != 0 {
+		t.Errorf("got %d open port-forwards, want 0", open)
+	}
+}
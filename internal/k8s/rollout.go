@@ -0,0 +1,476 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// RestartedAtAnnotation is the pod template annotation kubectl rollout restart sets
+	RestartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+	// RevisionAnnotation holds the revision number of a Deployment's ReplicaSet
+	RevisionAnnotation = "deployment.kubernetes.io/revision"
+	// ChangeCauseAnnotation records the reason of a change to a workload
+	ChangeCauseAnnotation = "kubernetes.io/change-cause"
+
+	// KindDeployment is the kind of apps/v1 Deployments
+	KindDeployment = "Deployment"
+	// KindStatefulSet is the kind of apps/v1 StatefulSets
+	KindStatefulSet = "StatefulSet"
+	// KindDaemonSet is the kind of apps/v1 DaemonSets
+	KindDaemonSet = "DaemonSet"
+)
+
+// RolloutCondition is a condition reported by a workload
+type RolloutCondition struct {
+	Type           string    `json:"type"`
+	Status         string    `json:"status"`
+	Reason         string    `json:"reason,omitempty"`
+	Message        string    `json:"message,omitempty"`
+	LastUpdateTime time.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// RolloutStatus reports the progress of a workload rollout, like kubectl rollout status
+type RolloutStatus struct {
+	Kind               string             `json:"kind"`
+	Name               string             `json:"name"`
+	Namespace          string             `json:"namespace"`
+	Generation         int64              `json:"generation"`
+	ObservedGeneration int64              `json:"observedGeneration"`
+	DesiredReplicas    int32              `json:"desiredReplicas"`
+	CurrentReplicas    int32              `json:"currentReplicas"`
+	UpdatedReplicas    int32              `json:"updatedReplicas"`
+	ReadyReplicas      int32              `json:"readyReplicas"`
+	AvailableReplicas  int32              `json:"availableReplicas"`
+	Paused             bool               `json:"paused,omitempty"`
+	Conditions         []RolloutCondition `json:"conditions,omitempty"`
+	// Done reports whether the rollout has completed
+	Done bool `json:"done"`
+	// Failed reports whether the rollout can no longer make progress
+	Failed  bool   `json:"failed,omitempty"`
+	Message string `json:"message"`
+}
+
+// resolveWorkloadKind resolves a resource type to one of the given apps/v1 workload kinds
+func (c *Client) resolveWorkloadKind(resourceType ResourceType, supported ...string) (string, error) {
+	mapping, err := c.findResourceMapping(resourceType)
+	if err != nil {
+		return "", err
+	}
+
+	gk := mapping.GroupVersionKind.GroupKind()
+	if gk.Group == appsv1.GroupName {
+		for _, kind := range supported {
+			if gk.Kind == kind {
+				return kind, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%s is not supported, supported kinds: %s", gk, strings.Join(supported, ", "))
+}
+
+// RestartRollout restarts the pods of a Deployment, StatefulSet or DaemonSet by setting the
+// restartedAt annotation on its pod template, like kubectl rollout restart
+func (c *Client) RestartRollout(ctx context.Context, resourceType ResourceType, name, namespace string, dryRun bool) (map[string]interface{}, error) {
+	kind, err := c.resolveWorkloadKind(resourceType, KindDeployment, KindStatefulSet, KindDaemonSet)
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == KindDeployment {
+		deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deployment: %w", err)
+		}
+		if deployment.Spec.Paused {
+			return nil, fmt.Errorf("cannot restart paused deployment %s, resume it first", name)
+		}
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{
+						RestartedAtAnnotation: time.Now().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build restart patch: %w", err)
+	}
+
+	return c.patchWorkload(ctx, kind, name, namespace, types.StrategicMergePatchType, patch, dryRun)
+}
+
+// PauseRollout pauses or resumes the rollout of a Deployment
+func (c *Client) PauseRollout(ctx context.Context, name, namespace string, paused, dryRun bool) (map[string]interface{}, error) {
+	deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+	if deployment.Spec.Paused == paused {
+		if paused {
+			return nil, fmt.Errorf("deployment %s is already paused", name)
+		}
+		return nil, fmt.Errorf("deployment %s is not paused", name)
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"paused": paused,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build pause patch: %w", err)
+	}
+
+	return c.patchWorkload(ctx, KindDeployment, name, namespace, types.StrategicMergePatchType, patch, dryRun)
+}
+
+// UndoRollout rolls a Deployment back to the pod template of a previous ReplicaSet revision.
+// A revision of 0 rolls back to the revision before the current one.
+func (c *Client) UndoRollout(ctx context.Context, name, namespace string, toRevision int64, dryRun bool) (map[string]interface{}, error) {
+	deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+	if deployment.Spec.Paused {
+		return nil, fmt.Errorf("cannot roll back paused deployment %s, resume it first", name)
+	}
+
+	replicaSets, err := c.deploymentReplicaSets(ctx, deployment)
+	if err != nil {
+		return nil, err
+	}
+	if len(replicaSets) == 0 {
+		return nil, fmt.Errorf("no rollout history found for deployment %s", name)
+	}
+
+	currentRevision := replicaSetRevision(&replicaSets[len(replicaSets)-1])
+	var target *appsv1.ReplicaSet
+	if toRevision == 0 {
+		// ReplicaSets are sorted by revision, so the previous revision is the second to last one
+		if len(replicaSets) < 2 {
+			return nil, fmt.Errorf("no previous revision found for deployment %s", name)
+		}
+		target = &replicaSets[len(replicaSets)-2]
+	} else {
+		for i := range replicaSets {
+			if replicaSetRevision(&replicaSets[i]) == toRevision {
+				target = &replicaSets[i]
+				break
+			}
+		}
+		if target == nil {
+			return nil, fmt.Errorf("revision %d not found for deployment %s", toRevision, name)
+		}
+		if toRevision == currentRevision {
+			return nil, fmt.Errorf("deployment %s is already at revision %d", name, toRevision)
+		}
+	}
+
+	// The pod-template-hash label is added by the controller and must not be copied back
+	template := target.Spec.Template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+
+	operations := []map[string]interface{}{
+		{"op": "replace", "path": "/spec/template", "value": template},
+	}
+	if changeCause, ok := target.Annotations[ChangeCauseAnnotation]; ok {
+		if deployment.Annotations == nil {
+			operations = append(operations, map[string]interface{}{
+				"op": "add", "path": "/metadata/annotations", "value": map[string]string{},
+			})
+		}
+		operations = append(operations, map[string]interface{}{
+			"op": "add", "path": "/metadata/annotations/" + escapeJSONPointer(ChangeCauseAnnotation), "value": changeCause,
+		})
+	}
+
+	patch, err := json.Marshal(operations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build rollback patch: %w", err)
+	}
+
+	return c.patchWorkload(ctx, KindDeployment, name, namespace, types.JSONPatchType, patch, dryRun)
+}
+
+// GetRolloutStatus reports the rollout progress of a Deployment, StatefulSet or DaemonSet
+func (c *Client) GetRolloutStatus(ctx context.Context, resourceType ResourceType, name, namespace string) (*RolloutStatus, error) {
+	kind, err := c.resolveWorkloadKind(resourceType, KindDeployment, KindStatefulSet, KindDaemonSet)
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case KindDeployment:
+		deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deployment: %w", err)
+		}
+		return deploymentRolloutStatus(deployment), nil
+	case KindStatefulSet:
+		statefulSet, err := c.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get statefulset: %w", err)
+		}
+		return statefulSetRolloutStatus(statefulSet), nil
+	default:
+		daemonSet, err := c.clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get daemonset: %w", err)
+		}
+		return daemonSetRolloutStatus(daemonSet), nil
+	}
+}
+
+// deploymentRolloutStatus computes the rollout status of a Deployment the way kubectl does
+func deploymentRolloutStatus(deployment *appsv1.Deployment) *RolloutStatus {
+	status := &RolloutStatus{
+		Kind:               KindDeployment,
+		Name:               deployment.Name,
+		Namespace:          deployment.Namespace,
+		Generation:         deployment.Generation,
+		ObservedGeneration: deployment.Status.ObservedGeneration,
+		DesiredReplicas:    int32Value(deployment.Spec.Replicas, 1),
+		CurrentReplicas:    deployment.Status.Replicas,
+		UpdatedReplicas:    deployment.Status.UpdatedReplicas,
+		ReadyReplicas:      deployment.Status.ReadyReplicas,
+		AvailableReplicas:  deployment.Status.AvailableReplicas,
+		Paused:             deployment.Spec.Paused,
+	}
+
+	var progressing *appsv1.DeploymentCondition
+	for i, condition := range deployment.Status.Conditions {
+		status.Conditions = append(status.Conditions, RolloutCondition{
+			Type:           string(condition.Type),
+			Status:         string(condition.Status),
+			Reason:         condition.Reason,
+			Message:        condition.Message,
+			LastUpdateTime: condition.LastUpdateTime.Time,
+		})
+		if condition.Type == appsv1.DeploymentProgressing {
+			progressing = &deployment.Status.Conditions[i]
+		}
+	}
+
+	switch {
+	case deployment.Generation > deployment.Status.ObservedGeneration:
+		status.Message = "Waiting for deployment spec update to be observed"
+	case progressing != nil && progressing.Reason == "ProgressDeadlineExceeded":
+		status.Failed = true
+		status.Message = fmt.Sprintf("deployment %q exceeded its progress deadline", deployment.Name)
+	case status.UpdatedReplicas < status.DesiredReplicas:
+		status.Message = fmt.Sprintf("Waiting for deployment %q rollout to finish: %d out of %d new replicas have been updated",
+			deployment.Name, status.UpdatedReplicas, status.DesiredReplicas)
+	case status.CurrentReplicas > status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("Waiting for deployment %q rollout to finish: %d old replicas are pending termination",
+			deployment.Name, status.CurrentReplicas-status.UpdatedReplicas)
+	case status.AvailableReplicas < status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("Waiting for deployment %q rollout to finish: %d of %d updated replicas are available",
+			deployment.Name, status.AvailableReplicas, status.UpdatedReplicas)
+	default:
+		status.Done = true
+		status.Message = fmt.Sprintf("deployment %q successfully rolled out", deployment.Name)
+	}
+
+	if status.Paused && !status.Done {
+		status.Message += " (rollout is paused)"
+	}
+
+	return status
+}
+
+// statefulSetRolloutStatus computes the rollout status of a StatefulSet the way kubectl does
+func statefulSetRolloutStatus(statefulSet *appsv1.StatefulSet) *RolloutStatus {
+	status := &RolloutStatus{
+		Kind:               KindStatefulSet,
+		Name:               statefulSet.Name,
+		Namespace:          statefulSet.Namespace,
+		Generation:         statefulSet.Generation,
+		ObservedGeneration: statefulSet.Status.ObservedGeneration,
+		DesiredReplicas:    int32Value(statefulSet.Spec.Replicas, 1),
+		CurrentReplicas:    statefulSet.Status.Replicas,
+		UpdatedReplicas:    statefulSet.Status.UpdatedReplicas,
+		ReadyReplicas:      statefulSet.Status.ReadyReplicas,
+		AvailableReplicas:  statefulSet.Status.AvailableReplicas,
+	}
+	for _, condition := range statefulSet.Status.Conditions {
+		status.Conditions = append(status.Conditions, RolloutCondition{
+			Type:           string(condition.Type),
+			Status:         string(condition.Status),
+			Reason:         condition.Reason,
+			Message:        condition.Message,
+			LastUpdateTime: condition.LastTransitionTime.Time,
+		})
+	}
+
+	rollingUpdate := statefulSet.Spec.UpdateStrategy.RollingUpdate
+	switch {
+	case statefulSet.Spec.UpdateStrategy.Type != appsv1.RollingUpdateStatefulSetStrategyType:
+		status.Done = true
+		status.Message = fmt.Sprintf("rollout status is only available for %s strategy type", appsv1.RollingUpdateStatefulSetStrategyType)
+	case statefulSet.Status.ObservedGeneration == 0 || statefulSet.Generation > statefulSet.Status.ObservedGeneration:
+		status.Message = "Waiting for statefulset spec update to be observed"
+	case status.ReadyReplicas < status.DesiredReplicas:
+		status.Message = fmt.Sprintf("Waiting for %d pods to be ready", status.DesiredReplicas-status.ReadyReplicas)
+	case rollingUpdate != nil && rollingUpdate.Partition != nil && *rollingUpdate.Partition > 0:
+		expected := status.DesiredReplicas - *rollingUpdate.Partition
+		if status.UpdatedReplicas < expected {
+			status.Message = fmt.Sprintf("Waiting for partitioned roll out to finish: %d out of %d new pods have been updated",
+				status.UpdatedReplicas, expected)
+		} else {
+			status.Done = true
+			status.Message = fmt.Sprintf("partitioned roll out complete: %d new pods have been updated", status.UpdatedReplicas)
+		}
+	case statefulSet.Status.UpdateRevision != statefulSet.Status.CurrentRevision:
+		status.Message = fmt.Sprintf("waiting for statefulset rolling update to complete %d pods at revision %s",
+			status.UpdatedReplicas, statefulSet.Status.UpdateRevision)
+	default:
+		status.Done = true
+		status.Message = fmt.Sprintf("statefulset rolling update complete %d pods at revision %s",
+			status.CurrentReplicas, statefulSet.Status.CurrentRevision)
+	}
+
+	return status
+}
+
+// daemonSetRolloutStatus computes the rollout status of a DaemonSet the way kubectl does
+func daemonSetRolloutStatus(daemonSet *appsv1.DaemonSet) *RolloutStatus {
+	status := &RolloutStatus{
+		Kind:               KindDaemonSet,
+		Name:               daemonSet.Name,
+		Namespace:          daemonSet.Namespace,
+		Generation:         daemonSet.Generation,
+		ObservedGeneration: daemonSet.Status.ObservedGeneration,
+		DesiredReplicas:    daemonSet.Status.DesiredNumberScheduled,
+		CurrentReplicas:    daemonSet.Status.CurrentNumberScheduled,
+		UpdatedReplicas:    daemonSet.Status.UpdatedNumberScheduled,
+		ReadyReplicas:      daemonSet.Status.NumberReady,
+		AvailableReplicas:  daemonSet.Status.NumberAvailable,
+	}
+	for _, condition := range daemonSet.Status.Conditions {
+		status.Conditions = append(status.Conditions, RolloutCondition{
+			Type:           string(condition.Type),
+			Status:         string(condition.Status),
+			Reason:         condition.Reason,
+			Message:        condition.Message,
+			LastUpdateTime: condition.LastTransitionTime.Time,
+		})
+	}
+
+	switch {
+	case daemonSet.Spec.UpdateStrategy.Type != appsv1.RollingUpdateDaemonSetStrategyType:
+		status.Done = true
+		status.Message = fmt.Sprintf("rollout status is only available for %s strategy type", appsv1.RollingUpdateDaemonSetStrategyType)
+	case daemonSet.Generation > daemonSet.Status.ObservedGeneration:
+		status.Message = "Waiting for daemon set spec update to be observed"
+	case status.UpdatedReplicas < status.DesiredReplicas:
+		status.Message = fmt.Sprintf("Waiting for daemon set %q rollout to finish: %d out of %d new pods have been updated",
+			daemonSet.Name, status.UpdatedReplicas, status.DesiredReplicas)
+	case status.AvailableReplicas < status.DesiredReplicas:
+		status.Message = fmt.Sprintf("Waiting for daemon set %q rollout to finish: %d of %d updated pods are available",
+			daemonSet.Name, status.AvailableReplicas, status.DesiredReplicas)
+	default:
+		status.Done = true
+		status.Message = fmt.Sprintf("daemon set %q successfully rolled out", daemonSet.Name)
+	}
+
+	return status
+}
+
+// deploymentReplicaSets returns the ReplicaSets owned by a Deployment sorted by ascending revision
+func (c *Client) deploymentReplicaSets(ctx context.Context, deployment *appsv1.Deployment) ([]appsv1.ReplicaSet, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deployment selector: %w", err)
+	}
+
+	list, err := c.clientset.AppsV1().ReplicaSets(deployment.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replicasets: %w", err)
+	}
+
+	var replicaSets []appsv1.ReplicaSet
+	for _, replicaSet := range list.Items {
+		if owner := metav1.GetControllerOf(&replicaSet); owner != nil && owner.UID == deployment.UID {
+			replicaSets = append(replicaSets, replicaSet)
+		}
+	}
+
+	sort.Slice(replicaSets, func(i, j int) bool {
+		return replicaSetRevision(&replicaSets[i]) < replicaSetRevision(&replicaSets[j])
+	})
+
+	return replicaSets, nil
+}
+
+// replicaSetRevision returns the Deployment revision recorded on a ReplicaSet, or 0 if unknown
+func replicaSetRevision(replicaSet *appsv1.ReplicaSet) int64 {
+	revision, err := strconv.ParseInt(replicaSet.Annotations[RevisionAnnotation], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return revision
+}
+
+// patchWorkload patches an apps/v1 workload and returns the patched object
+func (c *Client) patchWorkload(ctx context.Context, kind, name, namespace string, patchType types.PatchType, patch []byte, dryRun bool) (map[string]interface{}, error) {
+	options := metav1.PatchOptions{DryRun: dryRunOption(dryRun)}
+
+	var obj runtime.Object
+	var err error
+	switch kind {
+	case KindDeployment:
+		obj, err = c.clientset.AppsV1().Deployments(namespace).Patch(ctx, name, patchType, patch, options)
+	case KindStatefulSet:
+		obj, err = c.clientset.AppsV1().StatefulSets(namespace).Patch(ctx, name, patchType, patch, options)
+	case KindDaemonSet:
+		obj, err = c.clientset.AppsV1().DaemonSets(namespace).Patch(ctx, name, patchType, patch, options)
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %s", kind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch %s: %w", strings.ToLower(kind), err)
+	}
+
+	result, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s: %w", strings.ToLower(kind), err)
+	}
+
+	// Typed clients do not fill in the type information of returned objects
+	result["apiVersion"] = appsv1.SchemeGroupVersion.String()
+	result["kind"] = kind
+
+	return result, nil
+}
+
+// escapeJSONPointer escapes a key for use in a JSON Patch path
+func escapeJSONPointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// int32Value returns the value of an optional int32, or the default if it is unset
+func int32Value(value *int32, defaultValue int32) int32 {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
@@ -0,0 +1,279 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// RolloutRevision describes a single revision of a workload
+type RolloutRevision struct {
+	Revision int64 `json:"revision"`
+	// Source is the ReplicaSet or ControllerRevision holding the revision
+	Source      string    `json:"source"`
+	ChangeCause string    `json:"changeCause,omitempty"`
+	Images      []string  `json:"images"`
+	Created     time.Time `json:"created"`
+	Current     bool      `json:"current,omitempty"`
+}
+
+// RolloutHistory lists the revisions of a workload, oldest first, with an optional diff between two of them
+type RolloutHistory struct {
+	Kind      string            `json:"kind"`
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Revisions []RolloutRevision `json:"revisions"`
+	Diff      string            `json:"diff,omitempty"`
+}
+
+// workloadRevision is a revision together with the pod template it rolled out
+type workloadRevision struct {
+	RolloutRevision
+	template *corev1.PodTemplateSpec
+}
+
+// GetRolloutHistory lists the revisions of a Deployment, StatefulSet or DaemonSet. Deployment revisions
+// are read from the ReplicaSets it owns, StatefulSet and DaemonSet revisions from its ControllerRevisions.
+// If diffFrom is set, the pod templates of diffFrom and diffTo are compared, a diffTo of 0 compares
+// against the current revision.
+func (c *Client) GetRolloutHistory(ctx context.Context, resourceType ResourceType, name, namespace string, diffFrom, diffTo int64) (*RolloutHistory, error) {
+	kind, err := c.resolveWorkloadKind(resourceType, KindDeployment, KindStatefulSet, KindDaemonSet)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []workloadRevision
+	switch kind {
+	case KindDeployment:
+		revisions, err = c.deploymentRevisions(ctx, name, namespace)
+	case KindStatefulSet:
+		revisions, err = c.statefulSetRevisions(ctx, name, namespace)
+	default:
+		revisions, err = c.daemonSetRevisions(ctx, name, namespace)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	history := &RolloutHistory{
+		Kind:      kind,
+		Name:      name,
+		Namespace: namespace,
+		Revisions: make([]RolloutRevision, 0, len(revisions)),
+	}
+	for _, revision := range revisions {
+		history.Revisions = append(history.Revisions, revision.RolloutRevision)
+	}
+
+	if diffFrom > 0 {
+		history.Diff, err = diffRevisions(revisions, diffFrom, diffTo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return history, nil
+}
+
+// deploymentRevisions returns the revisions of a Deployment from the ReplicaSets it owns
+func (c *Client) deploymentRevisions(ctx context.Context, name, namespace string) ([]workloadRevision, error) {
+	deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	replicaSets, err := c.deploymentReplicaSets(ctx, deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	currentRevision := deployment.Annotations[RevisionAnnotation]
+	revisions := make([]workloadRevision, 0, len(replicaSets))
+	for i := range replicaSets {
+		replicaSet := &replicaSets[i]
+		template := replicaSet.Spec.Template.DeepCopy()
+		// The pod-template-hash label is added by the controller and differs between every revision
+		delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+
+		revisions = append(revisions, workloadRevision{
+			RolloutRevision: RolloutRevision{
+				Revision:    replicaSetRevision(replicaSet),
+				Source:      "ReplicaSet/" + replicaSet.Name,
+				ChangeCause: replicaSet.Annotations[ChangeCauseAnnotation],
+				Images:      podTemplateImages(template),
+				Created:     replicaSet.CreationTimestamp.Time,
+				Current:     currentRevision != "" && replicaSet.Annotations[RevisionAnnotation] == currentRevision,
+			},
+			template: template,
+		})
+	}
+
+	return revisions, nil
+}
+
+// statefulSetRevisions returns the revisions of a StatefulSet from its ControllerRevisions
+func (c *Client) statefulSetRevisions(ctx context.Context, name, namespace string) ([]workloadRevision, error) {
+	statefulSet, err := c.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statefulset: %w", err)
+	}
+
+	revisions, err := c.controllerRevisions(ctx, namespace, statefulSet.UID, statefulSet.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	for i := range revisions {
+		revisions[i].Current = revisions[i].Source == "ControllerRevision/"+statefulSet.Status.UpdateRevision
+	}
+
+	return revisions, nil
+}
+
+// daemonSetRevisions returns the revisions of a DaemonSet from its ControllerRevisions
+func (c *Client) daemonSetRevisions(ctx context.Context, name, namespace string) ([]workloadRevision, error) {
+	daemonSet, err := c.clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daemonset: %w", err)
+	}
+
+	revisions, err := c.controllerRevisions(ctx, namespace, daemonSet.UID, daemonSet.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	// The DaemonSet controller always rolls out the highest revision
+	if len(revisions) > 0 {
+		revisions[len(revisions)-1].Current = true
+	}
+
+	return revisions, nil
+}
+
+// controllerRevisions returns the ControllerRevisions owned by a workload sorted by ascending revision
+func (c *Client) controllerRevisions(ctx context.Context, namespace string, owner types.UID, labelSelector *metav1.LabelSelector) ([]workloadRevision, error) {
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workload selector: %w", err)
+	}
+
+	list, err := c.clientset.AppsV1().ControllerRevisions(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list controllerrevisions: %w", err)
+	}
+
+	var revisions []workloadRevision
+	for i := range list.Items {
+		controllerRevision := &list.Items[i]
+		if controller := metav1.GetControllerOf(controllerRevision); controller == nil || controller.UID != owner {
+			continue
+		}
+
+		template, err := controllerRevisionTemplate(controllerRevision)
+		if err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, workloadRevision{
+			RolloutRevision: RolloutRevision{
+				Revision:    controllerRevision.Revision,
+				Source:      "ControllerRevision/" + controllerRevision.Name,
+				ChangeCause: controllerRevision.Annotations[ChangeCauseAnnotation],
+				Images:      podTemplateImages(template),
+				Created:     controllerRevision.CreationTimestamp.Time,
+			},
+			template: template,
+		})
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+
+	return revisions, nil
+}
+
+// controllerRevisionTemplate extracts the pod template from a ControllerRevision. StatefulSet and
+// DaemonSet revisions store a patch of the form {"spec":{"template":{...,"$patch":"replace"}}}.
+func controllerRevisionTemplate(controllerRevision *appsv1.ControllerRevision) (*corev1.PodTemplateSpec, error) {
+	var data struct {
+		Spec struct {
+			Template corev1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}
+	if len(controllerRevision.Data.Raw) > 0 {
+		if err := json.Unmarshal(controllerRevision.Data.Raw, &data); err != nil {
+			return nil, fmt.Errorf("failed to parse controllerrevision %s: %w", controllerRevision.Name, err)
+		}
+	}
+	return &data.Spec.Template, nil
+}
+
+// podTemplateImages returns the images of the init and regular containers of a pod template
+func podTemplateImages(template *corev1.PodTemplateSpec) []string {
+	images := make([]string, 0, len(template.Spec.InitContainers)+len(template.Spec.Containers))
+	for _, container := range template.Spec.InitContainers {
+		images = append(images, container.Image)
+	}
+	for _, container := range template.Spec.Containers {
+		images = append(images, container.Image)
+	}
+	return images
+}
+
+// diffRevisions renders the difference between the pod templates of two revisions as a unified diff
+func diffRevisions(revisions []workloadRevision, from, to int64) (string, error) {
+	findRevision := func(revision int64) (*workloadRevision, error) {
+		for i := range revisions {
+			if (revision == 0 && revisions[i].Current) || (revision != 0 && revisions[i].Revision == revision) {
+				return &revisions[i], nil
+			}
+		}
+		if revision == 0 {
+			return nil, fmt.Errorf("current revision not found")
+		}
+		return nil, fmt.Errorf("revision %d not found", revision)
+	}
+
+	fromRevision, err := findRevision(from)
+	if err != nil {
+		return "", err
+	}
+	toRevision, err := findRevision(to)
+	if err != nil {
+		return "", err
+	}
+
+	before, err := templateObject(fromRevision.template)
+	if err != nil {
+		return "", err
+	}
+	after, err := templateObject(toRevision.template)
+	if err != nil {
+		return "", err
+	}
+
+	return unifiedDiff(before, after,
+		fmt.Sprintf("revision %d", fromRevision.Revision),
+		fmt.Sprintf("revision %d", toRevision.Revision))
+}
+
+// templateObject converts a pod template to a JSON-like map for diffing
+func templateObject(template *corev1.PodTemplateSpec) (map[string]interface{}, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert pod template: %w", err)
+	}
+	// Templates never have a creation timestamp, but the zero value is serialized as null
+	unstructured.RemoveNestedField(obj, "metadata", "creationTimestamp")
+	return obj, nil
+}
@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// testRevision returns a revision whose pod template runs the given image
+func testRevision(revision int64, image string, current bool) workloadRevision {
+	return workloadRevision{
+		RolloutRevision: RolloutRevision{Revision: revision, Images: []string{image}, Current: current},
+		template: &corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: image}}},
+		},
+	}
+}
+
+func TestDiffRevisions(t *testing.T) {
+	revisions := []workloadRevision{
+		testRevision(1, "nginx:1.25", false),
+		testRevision(2, "nginx:1.26", false),
+		testRevision(3, "nginx:1.26", true),
+	}
+
+	tests := []struct {
+		name     string
+		from, to int64
+		want     []string
+		wantErr  string
+	}{
+		{
+			name: "two revisions",
+			from: 1,
+			to:   2,
+			want: []string{"--- revision 1", "+++ revision 2", "-  - image: nginx:1.25", "+  - image: nginx:1.26"},
+		},
+		{
+			name: "against the current revision",
+			from: 1,
+			to:   0,
+			want: []string{"--- revision 1", "+++ revision 3", "-  - image: nginx:1.25", "+  - image: nginx:1.26"},
+		},
+		{
+			name: "identical templates",
+			from: 2,
+			to:   3,
+		},
+		{
+			name:    "unknown from revision",
+			from:    7,
+			to:      0,
+			wantErr: "revision 7 not found",
+		},
+		{
+			name:    "unknown to revision",
+			from:    1,
+			to:      9,
+			wantErr: "revision 9 not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := diffRevisions(revisions, tt.from, tt.to)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("diffRevisions error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("diffRevisions returned error: %v", err)
+			}
+
+			if len(tt.want) == 0 && diff != "" {
+				t.Errorf("diff of identical templates = %q, want empty", diff)
+			}
+			for _, line := range tt.want {
+				if !strings.Contains(diff, line+"\n") {
+					t.Errorf("diff does not contain %q:\n%s", line, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffRevisionsWithoutCurrentRevision(t *testing.T) {
+	_, err := diffRevisions([]workloadRevision{testRevision(1, "nginx:1.25", false)}, 1, 0)
+	if err == nil || err.Error() != "current revision not found" {
+		t.Errorf("diffRevisions error = %v, want current revision not found", err)
+	}
+}
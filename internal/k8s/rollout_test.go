@@ -0,0 +1,166 @@
+package k8s
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeploymentRolloutStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		generation int64
+		paused     bool
+		status     appsv1.DeploymentStatus
+		done       bool
+		failed     bool
+		message    string
+	}{
+		{
+			name:       "spec not observed",
+			generation: 2,
+			status:     appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3},
+			message:    "Waiting for deployment spec update to be observed",
+		},
+		{
+			name:       "progress deadline exceeded",
+			generation: 2,
+			status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 1,
+				Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"}}},
+			failed:  true,
+			message: `deployment "web" exceeded its progress deadline`,
+		},
+		{
+			name:       "replicas being updated",
+			generation: 2,
+			status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 1},
+			message:    `Waiting for deployment "web" rollout to finish: 1 out of 3 new replicas have been updated`,
+		},
+		{
+			name:       "old replicas terminating",
+			generation: 2,
+			status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 3},
+			message:    `Waiting for deployment "web" rollout to finish: 1 old replicas are pending termination`,
+		},
+		{
+			name:       "updated replicas unavailable",
+			generation: 2,
+			status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 2},
+			message:    `Waiting for deployment "web" rollout to finish: 2 of 3 updated replicas are available`,
+		},
+		{
+			name:       "paused",
+			generation: 2,
+			paused:     true,
+			status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 1},
+			message:    `Waiting for deployment "web" rollout to finish: 1 out of 3 new replicas have been updated (rollout is paused)`,
+		},
+		{
+			name:       "complete",
+			generation: 2,
+			status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, ReadyReplicas: 3, AvailableReplicas: 3},
+			done:       true,
+			message:    `deployment "web" successfully rolled out`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replicas := int32(3)
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: DefaultNamespace, Generation: tt.generation},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas, Paused: tt.paused},
+				Status:     tt.status,
+			}
+
+			status := deploymentRolloutStatus(deployment)
+			if status.Done != tt.done || status.Failed != tt.failed || status.Message != tt.message {
+				t.Errorf("deploymentRolloutStatus = done %v, failed %v, message %q, want done %v, failed %v, message %q",
+					status.Done, status.Failed, status.Message, tt.done, tt.failed, tt.message)
+			}
+		})
+	}
+}
+
+func TestStatefulSetRolloutStatus(t *testing.T) {
+	rollingUpdate := func(partition int32) appsv1.StatefulSetUpdateStrategy {
+		return appsv1.StatefulSetUpdateStrategy{
+			Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		strategy appsv1.StatefulSetUpdateStrategy
+		status   appsv1.StatefulSetStatus
+		done     bool
+		message  string
+	}{
+		{
+			name:     "on delete strategy",
+			strategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType},
+			done:     true,
+			message:  "rollout status is only available for RollingUpdate strategy type",
+		},
+		{
+			name:     "spec not observed",
+			strategy: rollingUpdate(0),
+			status:   appsv1.StatefulSetStatus{ObservedGeneration: 1, ReadyReplicas: 3},
+			message:  "Waiting for statefulset spec update to be observed",
+		},
+		{
+			name:     "pods not ready",
+			strategy: rollingUpdate(0),
+			status:   appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 1},
+			message:  "Waiting for 2 pods to be ready",
+		},
+		{
+			name:     "partition in progress",
+			strategy: rollingUpdate(1),
+			status:   appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 1},
+			message:  "Waiting for partitioned roll out to finish: 1 out of 2 new pods have been updated",
+		},
+		{
+			name:     "partition complete",
+			strategy: rollingUpdate(1),
+			status:   appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 2},
+			done:     true,
+			message:  "partitioned roll out complete: 2 new pods have been updated",
+		},
+		{
+			name:     "revisions differ",
+			strategy: rollingUpdate(0),
+			status: appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 2,
+				CurrentRevision: "db-1", UpdateRevision: "db-2"},
+			message: "waiting for statefulset rolling update to complete 2 pods at revision db-2",
+		},
+		{
+			name:     "complete",
+			strategy: rollingUpdate(0),
+			status: appsv1.StatefulSetStatus{ObservedGeneration: 2, Replicas: 3, ReadyReplicas: 3, UpdatedReplicas: 3,
+				CurrentRevision: "db-2", UpdateRevision: "db-2"},
+			done:    true,
+			message: "statefulset rolling update complete 3 pods at revision db-2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replicas := int32(3)
+			statefulSet := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: DefaultNamespace, Generation: 2},
+				Spec:       appsv1.StatefulSetSpec{Replicas: &replicas, UpdateStrategy: tt.strategy},
+				Status:     tt.status,
+			}
+
+			status := statefulSetRolloutStatus(statefulSet)
+			if status.Done != tt.done || status.Message != tt.message {
+				t.Errorf("statefulSetRolloutStatus = done %v, message %q, want done %v, message %q",
+					status.Done, status.Message, tt.done, tt.message)
+			}
+		})
+	}
+}
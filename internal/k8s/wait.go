@@ -0,0 +1,124 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// DefaultWaitTimeout is the default time WaitForCondition waits for
+const DefaultWaitTimeout = 60 * time.Second
+
+// WaitResult reports whether a resource met a condition before the timeout
+type WaitResult struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Condition string `json:"condition"`
+	Met       bool   `json:"met"`
+	TimedOut  bool   `json:"timedOut,omitempty"`
+	// Observed is the last value the condition looked at, e.g. the condition status or JSONPath output
+	Observed string `json:"observed,omitempty"`
+	Elapsed  string `json:"elapsed"`
+}
+
+// WaitForCondition waits until a resource meets a condition, like kubectl wait. The current state is
+// checked first and changes are then followed with a watch until the condition is met, the timeout
+// elapses or ctx is cancelled. A resource that does not exist yet is waited for.
+func (c *Client) WaitForCondition(ctx context.Context, resourceType ResourceType, name, namespace string, condition *Condition, timeout time.Duration) (*WaitResult, error) {
+	mapping, err := c.findResourceMapping(resourceType)
+	if err != nil {
+		return nil, err
+	}
+	gvr := mapping.Resource
+	if timeout <= 0 {
+		timeout = DefaultWaitTimeout
+	}
+
+	var resource dynamic.ResourceInterface = c.dynamicClient.Resource(gvr)
+	if namespace != "" {
+		resource = c.dynamicClient.Resource(gvr).Namespace(namespace)
+	}
+
+	start := time.Now()
+	result := &WaitResult{
+		Kind:      mapping.GroupVersionKind.Kind,
+		Namespace: namespace,
+		Name:      name,
+		Condition: condition.String(),
+	}
+
+	// evaluate records the state of the resource and reports whether it meets the condition,
+	// a nil object means the resource does not exist
+	evaluate := func(obj *unstructured.Unstructured) (bool, error) {
+		if obj == nil {
+			result.Observed = "not found"
+			return condition.WaitsForDeletion(), nil
+		}
+		met, observed, err := condition.Matches(obj.UnstructuredContent())
+		if err != nil {
+			return false, err
+		}
+		result.Observed = observed
+		return met, nil
+	}
+
+	// Listing by name instead of getting the resource returns a resource version to watch from
+	// even if the resource does not exist
+	options := metav1.ListOptions{FieldSelector: "metadata.name=" + name}
+	list, err := resource.List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource: %w", err)
+	}
+	var current *unstructured.Unstructured
+	if len(list.Items) > 0 {
+		current = &list.Items[0]
+	}
+	result.Met, err = evaluate(current)
+	if err != nil {
+		return nil, err
+	}
+
+	if !result.Met {
+		waitCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		handle := func(eventType watch.EventType, obj *unstructured.Unstructured) (bool, error) {
+			if eventType == watch.Deleted {
+				obj = nil
+			}
+			met, err := evaluate(obj)
+			if err != nil {
+				return false, err
+			}
+			result.Met = met
+			return met, nil
+		}
+		// After the watch had to be re-established, the fresh list holds the current state
+		resync := func(list *unstructured.UnstructuredList) (bool, error) {
+			if len(list.Items) == 0 {
+				return handle(watch.Deleted, nil)
+			}
+			return handle(watch.Modified, &list.Items[0])
+		}
+
+		err = watchEvents(waitCtx, resource, options, list.GetResourceVersion(), handle, resync)
+		if err != nil {
+			return nil, err
+		}
+		if !result.Met {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("wait for %s cancelled: %w", condition, ctx.Err())
+			}
+			result.TimedOut = true
+		}
+	}
+
+	result.Elapsed = time.Since(start).Round(time.Millisecond).String()
+	return result, nil
+}
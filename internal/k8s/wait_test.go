@@ -0,0 +1,75 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// testConfigMap returns a ConfigMap in the default namespace with the given data
+func testConfigMap(name string, data map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": DefaultNamespace,
+		},
+		"data": data,
+	}}
+}
+
+func TestWaitForConditionResumesAfterExpiredResourceVersion(t *testing.T) {
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{configMaps: "ConfigMapList"},
+		testConfigMap("settings", map[string]interface{}{"ready": "false"}))
+
+	watches := 0
+	dynamicClient.PrependWatchReactor("configmaps", func(action clienttesting.Action) (bool, watch.Interface, error) {
+		watches++
+		watcher := watch.NewFakeWithChanSize(1, false)
+		if watches == 1 {
+			// The resource changes while the watch is down, and the watch fails with 410 Gone
+			updated := testConfigMap("settings", map[string]interface{}{"ready": "true"})
+			if err := dynamicClient.Tracker().Update(configMaps, updated, DefaultNamespace); err != nil {
+				t.Errorf("failed to update config map: %v", err)
+			}
+			watcher.Error(&metav1.Status{
+				Status:  metav1.StatusFailure,
+				Code:    http.StatusGone,
+				Reason:  metav1.StatusReasonExpired,
+				Message: "too old resource version",
+			})
+		}
+		return true, watcher, nil
+	})
+
+	client := newFakeDiscoveryClient(testAPIResources)
+	client.dynamicClient = dynamicClient
+
+	condition, err := ParseCondition("jsonpath={.data.ready}=true")
+	if err != nil {
+		t.Fatalf("ParseCondition returned error: %v", err)
+	}
+
+	result, err := client.WaitForCondition(context.Background(), ResourceType{Name: "configmaps"}, "settings", DefaultNamespace, condition, 5*time.Second)
+	if err != nil {
+		t.Fatalf("WaitForCondition returned error: %v", err)
+	}
+	if !result.Met || result.TimedOut {
+		t.Errorf("WaitForCondition = %+v, want the condition met after the re-list", result)
+	}
+	if result.Observed != "true" {
+		t.Errorf("observed = %q, want %q", result.Observed, "true")
+	}
+}
@@ -0,0 +1,297 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// DefaultWatchMaxEvents is the default number of events collected by WatchResources
+	DefaultWatchMaxEvents = 200
+	// maxWatchEventChanges is the number of changed field paths reported per event
+	maxWatchEventChanges = 20
+
+	// WatchStoppedDuration means the watch duration elapsed
+	WatchStoppedDuration = "duration"
+	// WatchStoppedCondition means a watched resource met the condition
+	WatchStoppedCondition = "condition_met"
+	// WatchStoppedMaxEvents means the event budget was used up
+	WatchStoppedMaxEvents = "max_events"
+	// WatchStoppedCancelled means the caller cancelled the request
+	WatchStoppedCancelled = "cancelled"
+)
+
+// watchNoiseFields lists fields that change on every write and are not reported as changes
+var watchNoiseFields = [][]string{
+	{"metadata", "managedFields"},
+	{"metadata", "resourceVersion"},
+}
+
+// WatchEvent is a compact record of a change to a watched resource
+type WatchEvent struct {
+	Time      time.Time `json:"time"`
+	Type      string    `json:"type"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+	// Changes lists the field paths changed by a MODIFIED event
+	Changes []string `json:"changes,omitempty"`
+	// MoreChanges is the number of changed fields left out of Changes
+	MoreChanges int `json:"moreChanges,omitempty"`
+}
+
+// WatchResult is the change log collected by WatchResources
+type WatchResult struct {
+	Kind      string       `json:"kind"`
+	Namespace string       `json:"namespace,omitempty"`
+	Events    []WatchEvent `json:"events"`
+	// Stopped tells why the watch ended
+	Stopped string `json:"stopped"`
+	// MatchedResource is the resource that met the condition, if any
+	MatchedResource string `json:"matchedResource,omitempty"`
+}
+
+// WatchResources watches instances of a resource type for the given duration and returns the
+// ADDED, MODIFIED and DELETED events as a compact change log. Only changes made after the call are
+// reported. The watch ends early once maxEvents events were collected, or when a resource meets
+// the until condition if it is set.
+func (c *Client) WatchResources(ctx context.Context, resourceType ResourceType, namespace, labelSelector, fieldSelector string, duration time.Duration, until *Condition, maxEvents int) (*WatchResult, error) {
+	mapping, err := c.findResourceMapping(resourceType)
+	if err != nil {
+		return nil, err
+	}
+	gvr := mapping.Resource
+	if maxEvents <= 0 {
+		maxEvents = DefaultWatchMaxEvents
+	}
+
+	var resource dynamic.ResourceInterface = c.dynamicClient.Resource(gvr)
+	if namespace != "" {
+		resource = c.dynamicClient.Resource(gvr).Namespace(namespace)
+	}
+
+	options := metav1.ListOptions{
+		LabelSelector: labelSelector,
+		FieldSelector: fieldSelector,
+	}
+
+	// The initial list provides the resource version to watch from and the state that
+	// MODIFIED events are compared against
+	list, err := resource.List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resources: %w", err)
+	}
+	known := make(map[types.UID]map[string]interface{}, len(list.Items))
+	for _, item := range list.Items {
+		known[item.GetUID()] = stripWatchNoise(item.UnstructuredContent())
+	}
+
+	result := &WatchResult{
+		Kind:      mapping.GroupVersionKind.Kind,
+		Namespace: namespace,
+		Events:    []WatchEvent{},
+		Stopped:   WatchStoppedDuration,
+	}
+
+	watchCtx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	handle := func(eventType watch.EventType, obj *unstructured.Unstructured) (bool, error) {
+		event := WatchEvent{
+			Time:      time.Now().UTC(),
+			Type:      string(eventType),
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+		}
+
+		current := stripWatchNoise(obj.UnstructuredContent())
+		switch eventType {
+		case watch.Modified:
+			if previous, ok := known[obj.GetUID()]; ok {
+				for _, change := range diffFields("", previous, current) {
+					if len(event.Changes) == maxWatchEventChanges {
+						event.MoreChanges++
+						continue
+					}
+					event.Changes = append(event.Changes, change.Path)
+				}
+			}
+			known[obj.GetUID()] = current
+		case watch.Added:
+			known[obj.GetUID()] = current
+		case watch.Deleted:
+			delete(known, obj.GetUID())
+		}
+		result.Events = append(result.Events, event)
+
+		if until != nil {
+			met := eventType == watch.Deleted && until.WaitsForDeletion()
+			if eventType != watch.Deleted && !until.WaitsForDeletion() {
+				met, _, err = until.Matches(obj.UnstructuredContent())
+				if err != nil {
+					return false, err
+				}
+			}
+			if met {
+				result.Stopped = WatchStoppedCondition
+				result.MatchedResource = objectReference(obj)
+				return true, nil
+			}
+		}
+
+		if len(result.Events) >= maxEvents {
+			result.Stopped = WatchStoppedMaxEvents
+			return true, nil
+		}
+		return false, nil
+	}
+
+	// resync reports the changes missed while the watch was re-established by comparing a fresh
+	// list with the known state
+	resync := func(list *unstructured.UnstructuredList) (bool, error) {
+		listed := make(map[types.UID]bool, len(list.Items))
+		for i := range list.Items {
+			item := &list.Items[i]
+			listed[item.GetUID()] = true
+
+			eventType := watch.Added
+			if previous, ok := known[item.GetUID()]; ok {
+				if len(diffFields("", previous, stripWatchNoise(item.UnstructuredContent()))) == 0 {
+					continue
+				}
+				eventType = watch.Modified
+			}
+			if stop, err := handle(eventType, item); stop || err != nil {
+				return stop, err
+			}
+		}
+		for uid, previous := range known {
+			if listed[uid] {
+				continue
+			}
+			if stop, err := handle(watch.Deleted, &unstructured.Unstructured{Object: previous}); stop || err != nil {
+				return stop, err
+			}
+		}
+		return false, nil
+	}
+
+	err = watchEvents(watchCtx, resource, options, list.GetResourceVersion(), handle, resync)
+	if err != nil {
+		return nil, err
+	}
+	if result.Stopped == WatchStoppedDuration && ctx.Err() != nil {
+		result.Stopped = WatchStoppedCancelled
+	}
+
+	return result, nil
+}
+
+// watchEvents watches a resource starting at resourceVersion and passes every ADDED, MODIFIED and
+// DELETED event to handle until it returns true or ctx is done. Watches closed by the API server
+// are restarted from the last seen resource version. When that resource version has expired
+// (410 Gone), the resources are listed again to get a fresh one, and the list is passed to resync
+// so the caller can catch up with changes made in between.
+func watchEvents(ctx context.Context, resource dynamic.ResourceInterface, options metav1.ListOptions, resourceVersion string,
+	handle func(watch.EventType, *unstructured.Unstructured) (bool, error), resync func(*unstructured.UnstructuredList) (bool, error)) error {
+	relist := func() (bool, error) {
+		list, err := resource.List(ctx, metav1.ListOptions{
+			LabelSelector: options.LabelSelector,
+			FieldSelector: options.FieldSelector,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return true, nil
+			}
+			return true, fmt.Errorf("failed to list resources: %w", err)
+		}
+		resourceVersion = list.GetResourceVersion()
+		return resync(list)
+	}
+
+	for {
+		options.ResourceVersion = resourceVersion
+		options.AllowWatchBookmarks = true
+		watcher, err := resource.Watch(ctx, options)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if !isResourceVersionExpired(err) {
+				return fmt.Errorf("failed to watch resources: %w", err)
+			}
+			if stop, err := relist(); stop || err != nil {
+				return err
+			}
+			continue
+		}
+
+		stop, err := func() (bool, error) {
+			defer watcher.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return true, nil
+				case event, ok := <-watcher.ResultChan():
+					if !ok {
+						return false, nil
+					}
+					switch event.Type {
+					case watch.Error:
+						err := apierrors.FromObject(event.Object)
+						if !isResourceVersionExpired(err) {
+							return true, fmt.Errorf("watch failed: %w", err)
+						}
+						return relist()
+					case watch.Bookmark:
+						if obj, ok := event.Object.(*unstructured.Unstructured); ok {
+							resourceVersion = obj.GetResourceVersion()
+						}
+					case watch.Added, watch.Modified, watch.Deleted:
+						obj, ok := event.Object.(*unstructured.Unstructured)
+						if !ok {
+							continue
+						}
+						resourceVersion = obj.GetResourceVersion()
+						if stop, err := handle(event.Type, obj); stop || err != nil {
+							return true, err
+						}
+					}
+				}
+			}
+		}()
+		if stop || err != nil {
+			return err
+		}
+	}
+}
+
+// isResourceVersionExpired reports whether a watch failed because its resource version is too old
+func isResourceVersionExpired(err error) bool {
+	return apierrors.IsResourceExpired(err) || apierrors.IsGone(err)
+}
+
+// stripWatchNoise returns a copy of an object without fields that change on every write
+func stripWatchNoise(obj map[string]interface{}) map[string]interface{} {
+	stripped := runtime.DeepCopyJSON(obj)
+	for _, field := range watchNoiseFields {
+		unstructured.RemoveNestedField(stripped, field...)
+	}
+	return stripped
+}
+
+// objectReference returns the namespace/name of an object, or its name if it is cluster-scoped
+func objectReference(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
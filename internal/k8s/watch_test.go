@@ -0,0 +1,191 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+var testConfigMaps = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+// testWatchedConfigMap returns a ConfigMap with a UID, which WatchResources tracks resources by
+func testWatchedConfigMap(name string, data map[string]interface{}) *unstructured.Unstructured {
+	obj := testConfigMap(name, data)
+	obj.SetUID(types.UID(name + "-uid"))
+	return obj
+}
+
+// newFakeWatchClient returns a client whose watches are served by the given reactor
+func newFakeWatchClient(reactor func(dynamicClient *dynamicfake.FakeDynamicClient, watches int) watch.Interface, objects ...runtime.Object) *Client {
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{testConfigMaps: "ConfigMapList"}, objects...)
+
+	watches := 0
+	dynamicClient.PrependWatchReactor("configmaps", func(action clienttesting.Action) (bool, watch.Interface, error) {
+		watches++
+		return true, reactor(dynamicClient, watches), nil
+	})
+
+	client := newFakeDiscoveryClient(testAPIResources)
+	client.dynamicClient = dynamicClient
+	return client
+}
+
+func TestWatchResourcesResyncsAfterExpiredResourceVersion(t *testing.T) {
+	client := newFakeWatchClient(func(dynamicClient *dynamicfake.FakeDynamicClient, watches int) watch.Interface {
+		watcher := watch.NewFakeWithChanSize(1, false)
+		if watches > 1 {
+			return watcher
+		}
+
+		// The resources change while the watch is down, and the watch fails with 410 Gone
+		tracker := dynamicClient.Tracker()
+		if err := tracker.Update(testConfigMaps, testWatchedConfigMap("changed", map[string]interface{}{"key": "new"}), DefaultNamespace); err != nil {
+			t.Errorf("failed to update config map: %v", err)
+		}
+		if err := tracker.Delete(testConfigMaps, DefaultNamespace, "deleted"); err != nil {
+			t.Errorf("failed to delete config map: %v", err)
+		}
+		if err := tracker.Create(testConfigMaps, testWatchedConfigMap("added", nil), DefaultNamespace); err != nil {
+			t.Errorf("failed to create config map: %v", err)
+		}
+		watcher.Error(&metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusGone,
+			Reason:  metav1.StatusReasonExpired,
+			Message: "too old resource version",
+		})
+		return watcher
+	},
+		testWatchedConfigMap("changed", map[string]interface{}{"key": "old"}),
+		testWatchedConfigMap("deleted", nil),
+		testWatchedConfigMap("unchanged", nil),
+	)
+
+	result, err := client.WatchResources(context.Background(), ResourceType{Name: "configmaps"}, DefaultNamespace, "", "", 200*time.Millisecond, nil, 0)
+	if err != nil {
+		t.Fatalf("WatchResources returned error: %v", err)
+	}
+
+	got := map[string]WatchEvent{}
+	for _, event := range result.Events {
+		event.Time = time.Time{}
+		got[event.Name] = event
+	}
+	want := map[string]WatchEvent{
+		"changed": {Type: string(watch.Modified), Namespace: DefaultNamespace, Name: "changed", Changes: []string{".data.key"}},
+		"deleted": {Type: string(watch.Deleted), Namespace: DefaultNamespace, Name: "deleted"},
+		"added":   {Type: string(watch.Added), Namespace: DefaultNamespace, Name: "added"},
+	}
+	if len(result.Events) != len(want) || !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %+v, want %+v", result.Events, want)
+	}
+	if result.Stopped != WatchStoppedDuration {
+		t.Errorf("stopped = %q, want %q", result.Stopped, WatchStoppedDuration)
+	}
+}
+
+func TestWatchResourcesStops(t *testing.T) {
+	until, err := ParseCondition("jsonpath={.data.ready}=true")
+	if err != nil {
+		t.Fatalf("ParseCondition returned error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		until     *Condition
+		maxEvents int
+		cancel    bool
+		events    []map[string]interface{}
+		want      []string
+		stopped   string
+		matched   string
+	}{
+		{
+			name:    "duration",
+			want:    []string{},
+			stopped: WatchStoppedDuration,
+		},
+		{
+			name:    "duration after events",
+			events:  []map[string]interface{}{{"ready": "false"}, {"ready": "unknown"}},
+			want:    []string{"settings", "settings"},
+			stopped: WatchStoppedDuration,
+		},
+		{
+			name:    "until",
+			until:   until,
+			events:  []map[string]interface{}{{"ready": "false"}, {"ready": "true"}, {"ready": "false"}},
+			want:    []string{"settings", "settings"},
+			stopped: WatchStoppedCondition,
+			matched: DefaultNamespace + "/settings",
+		},
+		{
+			name:      "max events",
+			maxEvents: 2,
+			events:    []map[string]interface{}{{"ready": "false"}, {"ready": "true"}, {"ready": "false"}},
+			want:      []string{"settings", "settings"},
+			stopped:   WatchStoppedMaxEvents,
+		},
+		{
+			name:    "cancelled",
+			cancel:  true,
+			want:    []string{},
+			stopped: WatchStoppedCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeWatchClient(func(_ *dynamicfake.FakeDynamicClient, _ int) watch.Interface {
+				watcher := watch.NewFakeWithChanSize(len(tt.events), false)
+				for _, data := range tt.events {
+					watcher.Modify(testWatchedConfigMap("settings", data))
+				}
+				return watcher
+			}, testWatchedConfigMap("settings", map[string]interface{}{"ready": "unknown"}))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				cancel()
+			}
+
+			start := time.Now()
+			result, err := client.WatchResources(ctx, ResourceType{Name: "configmaps"}, DefaultNamespace, "", "", 200*time.Millisecond, tt.until, tt.maxEvents)
+			if err != nil {
+				t.Fatalf("WatchResources returned error: %v", err)
+			}
+
+			names := []string{}
+			for _, event := range result.Events {
+				names = append(names, event.Name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("events = %v, want %v", names, tt.want)
+			}
+			if result.Stopped != tt.stopped {
+				t.Errorf("stopped = %q, want %q", result.Stopped, tt.stopped)
+			}
+			if result.MatchedResource != tt.matched {
+				t.Errorf("matched resource = %q, want %q", result.MatchedResource, tt.matched)
+			}
+			if tt.stopped != WatchStoppedDuration && time.Since(start) >= 200*time.Millisecond {
+				t.Errorf("watch stopped by %s ran for the full duration", tt.stopped)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/silenceper/mcp-k8s/internal/k8s"
+)
+
+// CreateCopyFromPodTool creates a tool for copying files out of a pod container
+func CreateCopyFromPodTool() mcp.Tool {
+	return mcp.NewTool("copy_from_pod",
+		mcp.WithDescription("Copy a file or directory out of a pod container with tar, like kubectl cp. A single small text file is returned inline, larger or binary files and directories are written to the copy directory on the MCP server host and their local paths returned. The container must have a tar binary"),
+		mcp.WithString("pod_name",
+			mcp.Required(),
+			mcp.Description("Pod name"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description(fmt.Sprintf("Namespace (default: %s)", k8s.DefaultNamespace)),
+		),
+		mcp.WithString("container",
+			mcp.Description("Container name (optional, defaults to the default container of the pod)"),
+		),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Absolute path of the file or directory in the container"),
+		),
+		mcp.WithNumber("max_bytes",
+			mcp.Description(fmt.Sprintf("Size limit in bytes of the copied data (default: %d)", k8s.DefaultCopyMaxBytes)),
+			mcp.Min(1),
+		),
+		mcp.WithNumber("inline_bytes",
+			mcp.Description(fmt.Sprintf("Size up to which a single text file is returned inline (default: %d)", k8s.DefaultCopyInlineBytes)),
+			mcp.Min(0),
+		),
+	)
+}
+
+// CreateCopyToPodTool creates a tool for copying a file into a pod container
+func CreateCopyToPodTool() mcp.Tool {
+	return mcp.NewTool("copy_to_pod",
+		mcp.WithDescription("Write a file into a pod container with tar, like kubectl cp. The file content is given inline as text or read from a file in the copy directory on the MCP server host. The container must have a tar binary"),
+		mcp.WithString("pod_name",
+			mcp.Required(),
+			mcp.Description("Pod name"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description(fmt.Sprintf("Namespace (default: %s)", k8s.DefaultNamespace)),
+		),
+		mcp.WithString("container",
+			mcp.Description("Container name (optional, defaults to the default container of the pod)"),
+		),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Absolute path of the file in the container, missing parent directories are created"),
+		),
+		mcp.WithString("content",
+			mcp.Description("Text content of the file (use either content or local_path)"),
+		),
+		mcp.WithString("local_path",
+			mcp.Description("Path of a file in the copy directory on the MCP server host, e.g. one written by copy_from_pod (use either content or local_path)"),
+		),
+		mcp.WithNumber("max_bytes",
+			mcp.Description(fmt.Sprintf("Size limit in bytes of the copied file (default: %d)", k8s.DefaultCopyMaxBytes)),
+			mcp.Min(1),
+		),
+	)
+}
+
+// HandleCopyFromPod handles the copy from pod tool
+func HandleCopyFromPod(client *k8s.Client, copyDir string) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		podName, err := request.RequireString("pod_name")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: pod_name: %w", err)
+		}
+
+		remotePath, err := request.RequireString("path")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: path: %w", err)
+		}
+
+		namespace := request.GetString("namespace", k8s.DefaultNamespace)
+		container := request.GetString("container", "")
+		maxBytes := request.GetInt("max_bytes", k8s.DefaultCopyMaxBytes)
+		if maxBytes <= 0 {
+			return nil, fmt.Errorf("invalid max_bytes value: %d, must be positive", maxBytes)
+		}
+		inlineBytes := request.GetInt("inline_bytes", k8s.DefaultCopyInlineBytes)
+		if inlineBytes < 0 {
+			return nil, fmt.Errorf("invalid inline_bytes value: %d, must not be negative", inlineBytes)
+		}
+
+		result, err := client.CopyFromPod(ctx, namespace, podName, container, remotePath, copyDir, int64(maxBytes), int64(inlineBytes))
+		if err != nil {
+			return nil, err
+		}
+
+		jsonResponse, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}
+
+// HandleCopyToPod handles the copy to pod tool
+func HandleCopyToPod(client *k8s.Client, copyDir string) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		podName, err := request.RequireString("pod_name")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: pod_name: %w", err)
+		}
+
+		remotePath, err := request.RequireString("path")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: path: %w", err)
+		}
+
+		namespace := request.GetString("namespace", k8s.DefaultNamespace)
+		container := request.GetString("container", "")
+		maxBytes := request.GetInt("max_bytes", k8s.DefaultCopyMaxBytes)
+		if maxBytes <= 0 {
+			return nil, fmt.Errorf("invalid max_bytes value: %d, must be positive", maxBytes)
+		}
+
+		content, hasContent := request.GetArguments()["content"].(string)
+		localPath := request.GetString("local_path", "")
+		if hasContent == (localPath != "") {
+			return nil, fmt.Errorf("exactly one of content and local_path is required")
+		}
+
+		data := []byte(content)
+		if localPath != "" {
+			data, err = readCopyDirFile(copyDir, localPath, int64(maxBytes))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		result, err := client.CopyToPod(ctx, namespace, podName, container, remotePath, data, int64(maxBytes))
+		if err != nil {
+			return nil, err
+		}
+
+		jsonResponse, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}
+
+// readCopyDirFile reads a file that must be inside the copy directory, so the tool cannot be used
+// to read arbitrary files of the server host
+func readCopyDirFile(copyDir, localPath string, maxBytes int64) ([]byte, error) {
+	if copyDir == "" {
+		return nil, fmt.Errorf("local_path requires a copy directory to be configured")
+	}
+	root, err := filepath.Abs(copyDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid copy directory: %w", err)
+	}
+	if !filepath.IsAbs(localPath) {
+		localPath = filepath.Join(root, localPath)
+	}
+	localPath, err = filepath.EvalSymlinks(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve local_path: %w", err)
+	}
+	if resolvedRoot, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolvedRoot
+	}
+	relative, err := filepath.Rel(root, localPath)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("local_path must be inside the copy directory %s", copyDir)
+	}
+
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local_path: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("local_path %s is not a regular file", localPath)
+	}
+	if info.Size() > maxBytes {
+		return nil, fmt.Errorf("file size %d exceeds the copy size limit of %d bytes", info.Size(), maxBytes)
+	}
+
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local_path: %w", err)
+	}
+	return data, nil
+}
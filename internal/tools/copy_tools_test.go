@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadCopyDirFile(t *testing.T) {
+	base := t.TempDir()
+	copyDir := filepath.Join(base, "copy")
+	if err := os.MkdirAll(filepath.Join(copyDir, "nested"), 0o755); err != nil {
+		t.Fatalf("failed to create copy directory: %v", err)
+	}
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+	writeFile(filepath.Join(copyDir, "nested", "inside.txt"), "inside")
+	writeFile(filepath.Join(copyDir, "large.txt"), strings.Repeat("x", 100))
+	outside := filepath.Join(base, "outside.txt")
+	writeFile(outside, "outside")
+	// A sibling directory sharing the copy directory name as a prefix is still outside
+	if err := os.MkdirAll(copyDir+"-other", 0o755); err != nil {
+		t.Fatalf("failed to create sibling directory: %v", err)
+	}
+	writeFile(filepath.Join(copyDir+"-other", "sibling.txt"), "sibling")
+
+	if err := os.Symlink(outside, filepath.Join(copyDir, "escape.txt")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	if err := os.Symlink(base, filepath.Join(copyDir, "escape-dir")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(copyDir, "nested", "inside.txt"), filepath.Join(copyDir, "link.txt")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		copyDir   string
+		localPath string
+		want      string
+		wantErr   string
+	}{
+		{name: "relative path", copyDir: copyDir, localPath: "nested/inside.txt", want: "inside"},
+		{name: "absolute path inside", copyDir: copyDir, localPath: filepath.Join(copyDir, "nested", "inside.txt"), want: "inside"},
+		{name: "symlink inside", copyDir: copyDir, localPath: "link.txt", want: "inside"},
+		{name: "dot dot", copyDir: copyDir, localPath: "../outside.txt", wantErr: "local_path must be inside the copy directory"},
+		{name: "dot dot in the middle", copyDir: copyDir, localPath: "nested/../../outside.txt", wantErr: "local_path must be inside the copy directory"},
+		{name: "absolute path outside", copyDir: copyDir, localPath: outside, wantErr: "local_path must be inside the copy directory"},
+		{name: "sibling with the same prefix", copyDir: copyDir, localPath: "../copy-other/sibling.txt", wantErr: "local_path must be inside the copy directory"},
+		{name: "symlink to a file outside", copyDir: copyDir, localPath: "escape.txt", wantErr: "local_path must be inside the copy directory"},
+		{name: "symlink to a directory outside", copyDir: copyDir, localPath: "escape-dir/outside.txt", wantErr: "local_path must be inside the copy directory"},
+		{name: "directory", copyDir: copyDir, localPath: "nested", wantErr: "is not a regular file"},
+		{name: "missing file", copyDir: copyDir, localPath: "missing.txt", wantErr: "failed to resolve local_path"},
+		{name: "too large", copyDir: copyDir, localPath: "large.txt", wantErr: "file size 100 exceeds the copy size limit of 50 bytes"},
+		{name: "no copy directory", localPath: outside, wantErr: "local_path requires a copy directory to be configured"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := readCopyDirFile(tt.copyDir, tt.localPath, 50)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readCopyDirFile error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readCopyDirFile returned error: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("readCopyDirFile = %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/silenceper/mcp-k8s/internal/k8s"
+)
+
+// CreateDescribeResourceTool creates a tool for describing a resource
+func CreateDescribeResourceTool() mcp.Tool {
+	return mcp.NewTool("describe_resource",
+		mcp.WithDescription("Summarize a resource like kubectl describe: pods with container states, restarts, last termination reasons, conditions and volumes, deployments with their ReplicaSets and pods, services with their endpoints, and the recent events of the resource"),
+		mcp.WithString("kind",
+			mcp.Required(),
+			mcp.Description(ResourceKindDescription),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description(ResourceAPIVersionDescription),
+		),
+		mcp.WithString("group",
+			mcp.Description(ResourceGroupDescription),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Resource name"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description(fmt.Sprintf("Namespace (default: %s, ignored for cluster-scoped resources)", k8s.DefaultNamespace)),
+		),
+	)
+}
+
+// HandleDescribeResource handles the describe resource tool
+func HandleDescribeResource(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resourceType, err := getResourceType(request)
+		if err != nil {
+			return nil, err
+		}
+
+		name, err := request.RequireString("name")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: name: %w", err)
+		}
+
+		namespace := request.GetString("namespace", k8s.DefaultNamespace)
+
+		description, err := client.DescribeResource(ctx, resourceType, name, namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonResponse, err := json.Marshal(description)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}
@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/silenceper/mcp-k8s/internal/k8s"
+)
+
+// MaxExecTimeoutSeconds is the upper bound of the timeout of a command run in a pod
+const MaxExecTimeoutSeconds = 600
+
+// CreateExecInPodTool creates a tool for running a command in a pod container
+func CreateExecInPodTool() mcp.Tool {
+	return mcp.NewTool("exec_in_pod",
+		mcp.WithDescription("Run a non-interactive command in a pod container and return its stdout, stderr and exit code separately. The command is run directly, not through a shell, use [\"sh\", \"-c\", \"...\"] for shell syntax"),
+		mcp.WithString("pod_name",
+			mcp.Required(),
+			mcp.Description("Pod name"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description(fmt.Sprintf("Namespace (default: %s)", k8s.DefaultNamespace)),
+		),
+		mcp.WithString("container",
+			mcp.Description("Container name (optional, defaults to the default container of the pod)"),
+		),
+		mcp.WithArray("command",
+			mcp.Required(),
+			mcp.Description("Command and arguments, e.g. [\"ls\", \"-l\", \"/tmp\"]"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("stdin",
+			mcp.Description("Text passed to the standard input of the command (optional)"),
+		),
+		mcp.WithNumber("timeout_seconds",
+			mcp.Description(fmt.Sprintf("Time limit of the command in seconds (default: %d, max: %d)", int(k8s.DefaultExecTimeout.Seconds()), MaxExecTimeoutSeconds)),
+			mcp.Min(1),
+			mcp.Max(MaxExecTimeoutSeconds),
+		),
+		mcp.WithNumber("max_output_bytes",
+			mcp.Description(fmt.Sprintf("Size limit in bytes of each of stdout and stderr, further output is discarded (default: %d)", k8s.DefaultExecMaxOutputBytes)),
+			mcp.Min(1),
+		),
+	)
+}
+
+// HandleExecInPod handles the exec in pod tool
+func HandleExecInPod(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		podName, err := request.RequireString("pod_name")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: pod_name: %w", err)
+		}
+
+		command, err := request.RequireStringSlice("command")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: command: %w", err)
+		}
+
+		namespace := request.GetString("namespace", k8s.DefaultNamespace)
+		container := request.GetString("container", "")
+		stdin := request.GetString("stdin", "")
+
+		timeoutSeconds := request.GetInt("timeout_seconds", int(k8s.DefaultExecTimeout.Seconds()))
+		if timeoutSeconds <= 0 || timeoutSeconds > MaxExecTimeoutSeconds {
+			return nil, fmt.Errorf("invalid timeout_seconds value: %d, must be between 1 and %d", timeoutSeconds, MaxExecTimeoutSeconds)
+		}
+		maxOutputBytes := request.GetInt("max_output_bytes", k8s.DefaultExecMaxOutputBytes)
+		if maxOutputBytes <= 0 {
+			return nil, fmt.Errorf("invalid max_output_bytes value: %d, must be positive", maxOutputBytes)
+		}
+
+		result, err := client.ExecInPod(ctx, namespace, podName, container, command, stdin, time.Duration(timeoutSeconds)*time.Second, maxOutputBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonResponse, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}
@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/silenceper/mcp-k8s/internal/k8s"
+)
+
+// withTopOptions returns the sorting and top-N options shared by the top tools
+func withTopOptions(percentOf string) []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("label_selector",
+			mcp.Description("Label selector (format: key1=value1,key2=value2)"),
+		),
+		mcp.WithString("sort_by",
+			mcp.Description(fmt.Sprintf("Sort key, highest first: cpu, memory, cpu_percent, memory_percent (usage relative to %s) or name (default: cpu)", percentOf)),
+			mcp.Enum(k8s.TopSortCPU, k8s.TopSortMemory, k8s.TopSortCPUPercent, k8s.TopSortMemoryPercent, k8s.TopSortName),
+			mcp.DefaultString(k8s.TopSortCPU),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of results to return, 0 for all (default: %d)", k8s.DefaultTopLimit)),
+			mcp.Min(0),
+		),
+	}
+}
+
+// CreateTopNodesTool creates a tool for getting the resource usage of nodes
+func CreateTopNodesTool() mcp.Tool {
+	options := []mcp.ToolOption{
+		mcp.WithDescription("Show the CPU and memory usage of nodes from the metrics API (requires metrics-server), with the usage as a percentage of the node allocatable, like kubectl top nodes"),
+	}
+	options = append(options, withTopOptions("the node allocatable")...)
+
+	return mcp.NewTool("top_nodes", options...)
+}
+
+// CreateTopPodsTool creates a tool for getting the resource usage of pods
+func CreateTopPodsTool() mcp.Tool {
+	options := []mcp.ToolOption{
+		mcp.WithDescription("Show the CPU and memory usage of pods from the metrics API (requires metrics-server), with their requests and limits and the usage as a percentage of them, like kubectl top pods"),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace (default: all namespaces)"),
+		),
+		mcp.WithBoolean("containers",
+			mcp.Description("Include the usage of each container"),
+			mcp.DefaultBool(false),
+		),
+	}
+	options = append(options, withTopOptions("the pod requests")...)
+
+	return mcp.NewTool("top_pods", options...)
+}
+
+// getTopLimit reads the limit parameter of a top tool request
+func getTopLimit(request mcp.CallToolRequest) (int, error) {
+	limit := request.GetInt("limit", k8s.DefaultTopLimit)
+	if limit < 0 {
+		return 0, fmt.Errorf("invalid limit value: %d, must not be negative", limit)
+	}
+	return limit, nil
+}
+
+// HandleTopNodes handles the top nodes tool
+func HandleTopNodes(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		limit, err := getTopLimit(request)
+		if err != nil {
+			return nil, err
+		}
+
+		labelSelector := request.GetString("label_selector", "")
+		sortBy := request.GetString("sort_by", k8s.TopSortCPU)
+
+		nodes, err := client.TopNodes(ctx, labelSelector, sortBy, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonResponse, err := json.Marshal(nodes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}
+
+// HandleTopPods handles the top pods tool
+func HandleTopPods(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		limit, err := getTopLimit(request)
+		if err != nil {
+			return nil, err
+		}
+
+		namespace := request.GetString("namespace", "")
+		labelSelector := request.GetString("label_selector", "")
+		sortBy := request.GetString("sort_by", k8s.TopSortCPU)
+		containers := request.GetBool("containers", false)
+
+		pods, err := client.TopPods(ctx, namespace, labelSelector, sortBy, limit, containers)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonResponse, err := json.Marshal(pods)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}
@@ -0,0 +1,345 @@
+package tools
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/silenceper/mcp-k8s/internal/k8s"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	// lastAppliedConfigAnnotation is written by kubectl apply and duplicates the whole object
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+	// KeepAll keeps every field of a resource
+	KeepAll = "all"
+	// KeepSpec keeps only the identity, metadata and spec of a resource
+	KeepSpec = "spec"
+	// KeepStatus keeps only the identity, metadata and status of a resource
+	KeepStatus = "status"
+
+	// OutputJSON returns list results as JSON objects
+	OutputJSON = "json"
+	// OutputTable returns list results as the table kubectl get prints
+	OutputTable = "table"
+	// OutputWide returns list results as the table kubectl get -o wide prints
+	OutputWide = "wide"
+)
+
+// OutputConfig holds the server-wide defaults for shaping resources returned by the read tools
+type OutputConfig struct {
+	// Sanitize drops managedFields and the last-applied-configuration annotation unless a call overrides it
+	Sanitize bool
+	// ListMaxResponseBytes is the size limit of a list_resources response, 0 disables the limit
+	ListMaxResponseBytes int
+}
+
+// outputOptions controls how resources are shaped for a single call
+type outputOptions struct {
+	sanitize bool
+	keep     string
+	// jsonPath is a kubectl-style JSONPath template evaluated against the result
+	jsonPath string
+	// fields are dotted field paths projected into a flat map per resource
+	fields [][]string
+}
+
+// relaxedJSONPathPattern matches a single JSONPath expression with optional braces and leading dot
+var relaxedJSONPathPattern = regexp.MustCompile(`^\{\.?([^{}]+)\}$|^\.?([^{}]+)$`)
+
+// withOutputOptions adds the parameters that control how returned resources are shaped
+func withOutputOptions() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithBoolean("sanitize",
+			mcp.Description("Drop metadata.managedFields and the kubectl last-applied-configuration annotation (default: server setting, usually true)"),
+		),
+		mcp.WithString("keep",
+			mcp.Description("Fields to keep: all (default), spec, status, or a comma-separated list of field paths (e.g. metadata.labels,spec.replicas). apiVersion, kind, name and namespace are always kept"),
+		),
+		mcp.WithString("jsonpath",
+			mcp.Description("kubectl-style JSONPath template evaluated against the result and returned as text, e.g. {.spec.replicas}, or {.items[*].status.phase} for lists"),
+		),
+		mcp.WithString("fields",
+			mcp.Description("Comma-separated field paths to return as a flat object per resource, e.g. metadata.name,spec.replicas"),
+		),
+	}
+}
+
+// getOutputOptions reads the output shaping parameters of a request
+func getOutputOptions(request mcp.CallToolRequest, config OutputConfig) (outputOptions, error) {
+	opts := outputOptions{
+		sanitize: request.GetBool("sanitize", config.Sanitize),
+		keep:     strings.TrimSpace(request.GetString("keep", KeepAll)),
+		jsonPath: strings.TrimSpace(request.GetString("jsonpath", "")),
+	}
+
+	if fields := strings.TrimSpace(request.GetString("fields", "")); fields != "" {
+		for _, field := range strings.Split(fields, ",") {
+			path, err := parseFieldPath(field)
+			if err != nil {
+				return opts, err
+			}
+			opts.fields = append(opts.fields, path)
+		}
+	}
+
+	if opts.jsonPath != "" && len(opts.fields) > 0 {
+		return opts, fmt.Errorf("jsonpath and fields cannot be used together")
+	}
+
+	return opts, nil
+}
+
+// shapingParameters returns the output shaping parameters set by a request, in the order they are
+// declared. Empty strings count as unset, like they do when the options are read.
+func shapingParameters(request mcp.CallToolRequest) []string {
+	arguments := request.GetArguments()
+
+	var set []string
+	for _, name := range []string{"sanitize", "keep", "jsonpath", "fields"} {
+		value, ok := arguments[name]
+		if !ok || value == nil {
+			continue
+		}
+		if text, isString := value.(string); isString && strings.TrimSpace(text) == "" {
+			continue
+		}
+		set = append(set, name)
+	}
+	return set
+}
+
+// shapeResource applies the output options to a resource, modifying it in place where possible
+func shapeResource(obj map[string]interface{}, opts outputOptions) (map[string]interface{}, error) {
+	if opts.sanitize {
+		sanitizeResource(obj)
+	}
+
+	switch opts.keep {
+	case "", KeepAll:
+		return obj, nil
+	case KeepSpec, KeepStatus:
+		return keepFields(obj, [][]string{{"metadata"}, {opts.keep}}), nil
+	default:
+		var paths [][]string
+		for _, field := range strings.Split(opts.keep, ",") {
+			path, err := parseFieldPath(field)
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, path)
+		}
+		return keepFields(obj, paths), nil
+	}
+}
+
+// shapeResources applies the output options to every resource of a list
+func shapeResources(items []map[string]interface{}, opts outputOptions) ([]map[string]interface{}, error) {
+	for i, item := range items {
+		shaped, err := shapeResource(item, opts)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = shaped
+	}
+	return items, nil
+}
+
+// sanitizeResource drops metadata that is rarely useful to a reader but often dominates the payload
+func sanitizeResource(obj map[string]interface{}) {
+	unstructured.RemoveNestedField(obj, "metadata", "managedFields")
+
+	annotations, found, err := unstructured.NestedMap(obj, "metadata", "annotations")
+	if err != nil || !found {
+		return
+	}
+	if _, ok := annotations[lastAppliedConfigAnnotation]; !ok {
+		return
+	}
+
+	unstructured.RemoveNestedField(obj, "metadata", "annotations", lastAppliedConfigAnnotation)
+	if len(annotations) == 1 {
+		unstructured.RemoveNestedField(obj, "metadata", "annotations")
+	}
+}
+
+// keepFields returns a copy of the resource with only its identity and the given field paths
+func keepFields(obj map[string]interface{}, paths [][]string) map[string]interface{} {
+	identity := [][]string{
+		{"apiVersion"},
+		{"kind"},
+		{"metadata", "name"},
+		{"metadata", "namespace"},
+	}
+
+	kept := map[string]interface{}{}
+	for _, path := range append(identity, paths...) {
+		value, found, err := unstructured.NestedFieldNoCopy(obj, path...)
+		if err != nil || !found {
+			continue
+		}
+		// Errors only occur when a parent of the path is not a map, which cannot happen
+		// here since the same path exists in the source object
+		_ = unstructured.SetNestedField(kept, value, path...)
+	}
+
+	return kept
+}
+
+// parseFieldPath parses a dotted field path such as .spec.replicas or metadata.labels
+func parseFieldPath(field string) ([]string, error) {
+	field = strings.TrimPrefix(strings.TrimSpace(field), ".")
+	if field == "" {
+		return nil, fmt.Errorf("invalid field path: path must not be empty")
+	}
+
+	path := strings.Split(field, ".")
+	for _, part := range path {
+		if part == "" {
+			return nil, fmt.Errorf("invalid field path %q", field)
+		}
+	}
+	return path, nil
+}
+
+// projectFields returns a flat map from each dotted field path to its value in the resource,
+// missing fields are returned as null
+func projectFields(obj map[string]interface{}, fields [][]string) map[string]interface{} {
+	projected := make(map[string]interface{}, len(fields))
+	for _, path := range fields {
+		value, found, err := unstructured.NestedFieldNoCopy(obj, path...)
+		if err != nil || !found {
+			value = nil
+		}
+		projected[strings.Join(path, ".")] = value
+	}
+	return projected
+}
+
+// projectResources replaces every resource of a list with its projected fields
+func projectResources(items []map[string]interface{}, fields [][]string) []map[string]interface{} {
+	for i, item := range items {
+		items[i] = projectFields(item, fields)
+	}
+	return items
+}
+
+// evaluateJSONPath evaluates a kubectl-style JSONPath template against data and returns the text output
+func evaluateJSONPath(template string, data interface{}) (string, error) {
+	parser := jsonpath.New("output").AllowMissingKeys(true)
+	if err := parser.Parse(relaxedJSONPath(template)); err != nil {
+		return "", fmt.Errorf("invalid jsonpath %q: %w", template, err)
+	}
+
+	var buf bytes.Buffer
+	if err := parser.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to evaluate jsonpath %q: %w", template, err)
+	}
+	return buf.String(), nil
+}
+
+// relaxedJSONPath accepts the same shorthand as kubectl, e.g. .spec.replicas or spec.replicas for
+// {.spec.replicas}. Templates with several expressions, such as range blocks, are used as given.
+func relaxedJSONPath(template string) string {
+	matches := relaxedJSONPathPattern.FindStringSubmatch(template)
+	if matches == nil {
+		return template
+	}
+
+	expression := matches[1]
+	if expression == "" {
+		expression = matches[2]
+	}
+	return "{." + expression + "}"
+}
+
+// renderTable renders a resource table as aligned text like kubectl get. Columns with a priority
+// above 0 are only included in wide output, and a namespace column is added when rows span namespaces.
+func renderTable(table *k8s.ResourceTable, wide, showNamespace bool) string {
+	var columns []int
+	for i, column := range table.Columns {
+		if wide || column.Priority == 0 {
+			columns = append(columns, i)
+		}
+	}
+
+	var buf bytes.Buffer
+	writer := tabwriter.NewWriter(&buf, 0, 8, 3, ' ', 0)
+
+	var header []string
+	if showNamespace {
+		header = append(header, "NAMESPACE")
+	}
+	for _, i := range columns {
+		header = append(header, strings.ToUpper(table.Columns[i].Name))
+	}
+	fmt.Fprintln(writer, strings.Join(header, "\t"))
+
+	for _, row := range table.Rows {
+		var cells []string
+		if showNamespace {
+			cells = append(cells, row.Namespace)
+		}
+		for _, i := range columns {
+			cells = append(cells, formatTableCell(row.Cells, i))
+		}
+		fmt.Fprintln(writer, strings.Join(cells, "\t"))
+	}
+	_ = writer.Flush()
+
+	if table.Continue != "" {
+		fmt.Fprintf(&buf, "\nMore results available, continue token: %s\n", table.Continue)
+	}
+
+	return buf.String()
+}
+
+// renderTableWithinBytes renders a table like renderTable, keeping only the leading rows that fit in
+// maxBytes together with a note suggesting that number of rows as the limit to page with. A
+// maxBytes of 0 disables the limit.
+func renderTableWithinBytes(table *k8s.ResourceTable, wide, showNamespace bool, maxBytes int) (string, error) {
+	text := renderTable(table, wide, showNamespace)
+	if maxBytes <= 0 || len(text) <= maxBytes {
+		return text, nil
+	}
+
+	render := func(rows int) string {
+		truncated := *table
+		truncated.Rows = table.Rows[:rows]
+		// The continue token points after all rows, so it cannot be used for the truncated table
+		truncated.Continue = ""
+		return renderTable(&truncated, wide, showNamespace) + fmt.Sprintf(
+			"\nTruncated to %d of %d rows to fit the response size limit, list with limit %d to page through all of them\n",
+			rows, len(table.Rows), rows)
+	}
+
+	// Find the largest number of rows that fits, the rendered size grows with the number of rows
+	low, high := 0, len(table.Rows)-1
+	for low < high {
+		mid := (low + high + 1) / 2
+		if len(render(mid)) <= maxBytes {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	if low == 0 {
+		return "", fmt.Errorf("a single table row exceeds the response size limit of %d bytes", maxBytes)
+	}
+
+	return render(low), nil
+}
+
+// formatTableCell formats a table cell the way kubectl prints it
+func formatTableCell(cells []interface{}, i int) string {
+	if i >= len(cells) || cells[i] == nil {
+		return "<none>"
+	}
+	return fmt.Sprint(cells[i])
+}
@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/silenceper/mcp-k8s/internal/k8s"
+)
+
+// CreateStartPortForwardTool creates a tool for starting a port-forward to a pod or service
+func CreateStartPortForwardTool() mcp.Tool {
+	return mcp.NewTool("start_port_forward",
+		mcp.WithDescription("Forward a local port on the MCP server host to a port of a pod or service, like kubectl port-forward. A service is resolved to a ready pod backing it. The port-forward stays open until it is stopped or has been idle for the idle timeout"),
+		mcp.WithString("kind",
+			mcp.Description("Target kind: pod or service (default: pod)"),
+			mcp.Enum("pod", "service"),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Pod or service name"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description(fmt.Sprintf("Namespace (default: %s)", k8s.DefaultNamespace)),
+		),
+		mcp.WithNumber("remote_port",
+			mcp.Required(),
+			mcp.Description("Port of the pod, or port of the service which is translated to the target port of the pod"),
+			mcp.Min(1),
+			mcp.Max(65535),
+		),
+		mcp.WithNumber("local_port",
+			mcp.Description("Local port to listen on (default: a free port)"),
+			mcp.Min(0),
+			mcp.Max(65535),
+		),
+		mcp.WithString("local_address",
+			mcp.Description(fmt.Sprintf("Local loopback address to listen on, other addresses are rejected (default: %s)", k8s.DefaultPortForwardAddress)),
+		),
+		mcp.WithNumber("idle_timeout_seconds",
+			mcp.Description(fmt.Sprintf("Close the port-forward after it had no connections for this many seconds (default: %d)", int(k8s.DefaultPortForwardIdleTimeout.Seconds()))),
+			mcp.Min(1),
+		),
+	)
+}
+
+// CreateListPortForwardsTool creates a tool for listing the open port-forwards
+func CreateListPortForwardsTool() mcp.Tool {
+	return mcp.NewTool("list_port_forwards",
+		mcp.WithDescription("List the port-forwards opened by start_port_forward with their local address, target and connection activity"),
+	)
+}
+
+// CreateStopPortForwardTool creates a tool for stopping a port-forward
+func CreateStopPortForwardTool() mcp.Tool {
+	return mcp.NewTool("stop_port_forward",
+		mcp.WithDescription("Stop a port-forward opened by start_port_forward"),
+		mcp.WithString("id",
+			mcp.Required(),
+			mcp.Description("Port-forward ID returned by start_port_forward"),
+		),
+	)
+}
+
+// HandleStartPortForward handles the start port-forward tool
+func HandleStartPortForward(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: name: %w", err)
+		}
+
+		remotePort, err := request.RequireInt("remote_port")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: remote_port: %w", err)
+		}
+
+		kind := request.GetString("kind", "pod")
+		namespace := request.GetString("namespace", k8s.DefaultNamespace)
+		localPort := request.GetInt("local_port", 0)
+		localAddress := request.GetString("local_address", k8s.DefaultPortForwardAddress)
+		idleTimeoutSeconds := request.GetInt("idle_timeout_seconds", int(k8s.DefaultPortForwardIdleTimeout.Seconds()))
+		if idleTimeoutSeconds <= 0 {
+			return nil, fmt.Errorf("invalid idle_timeout_seconds value: %d, must be positive", idleTimeoutSeconds)
+		}
+
+		session, err := client.StartPortForward(ctx, namespace, kind, name, remotePort, localAddress, localPort, time.Duration(idleTimeoutSeconds)*time.Second)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonResponse, err := json.Marshal(session)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}
+
+// HandleListPortForwards handles the list port-forwards tool
+func HandleListPortForwards(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		jsonResponse, err := json.Marshal(client.ListPortForwards())
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}
+
+// HandleStopPortForward handles the stop port-forward tool
+func HandleStopPortForward(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := request.RequireString("id")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: id: %w", err)
+		}
+
+		session, err := client.StopPortForward(id)
+		if err != nil {
+			return nil, err
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Successfully stopped port-forward %s from %s:%d to %s port %d in namespace %s",
+			session.ID, session.LocalAddress, session.LocalPort, session.Target, session.RemotePort, session.Namespace)), nil
+	}
+}
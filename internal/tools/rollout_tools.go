@@ -0,0 +1,292 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/silenceper/mcp-k8s/internal/k8s"
+)
+
+const (
+	// WorkloadKindDescription describes the kind parameter of the rollout tools
+	WorkloadKindDescription = "Workload kind: Deployment, StatefulSet or DaemonSet (default: Deployment)"
+)
+
+// CreateRolloutRestartTool creates a tool for restarting a workload rollout
+func CreateRolloutRestartTool() mcp.Tool {
+	return mcp.NewTool("rollout_restart",
+		mcp.WithDescription("Restart all pods of a Deployment, StatefulSet or DaemonSet with a rolling update, like kubectl rollout restart"),
+		mcp.WithString("kind",
+			mcp.Description(WorkloadKindDescription),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Workload name"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description(fmt.Sprintf("Namespace (default: %s)", k8s.DefaultNamespace)),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description(DryRunDescription),
+			mcp.DefaultBool(false),
+		),
+	)
+}
+
+// CreateRolloutStatusTool creates a tool for getting the rollout status of a workload
+func CreateRolloutStatusTool() mcp.Tool {
+	return mcp.NewTool("rollout_status",
+		mcp.WithDescription("Get the rollout progress of a Deployment, StatefulSet or DaemonSet: observed generation, updated, ready and available replicas, conditions and whether the rollout is done"),
+		mcp.WithString("kind",
+			mcp.Description(WorkloadKindDescription),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Workload name"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description(fmt.Sprintf("Namespace (default: %s)", k8s.DefaultNamespace)),
+		),
+	)
+}
+
+// CreateRolloutPauseTool creates a tool for pausing a Deployment rollout
+func CreateRolloutPauseTool() mcp.Tool {
+	return mcp.NewTool("rollout_pause",
+		mcp.WithDescription("Pause the rollout of a Deployment, changes to its pod template are not rolled out until it is resumed"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Deployment name"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description(fmt.Sprintf("Namespace (default: %s)", k8s.DefaultNamespace)),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description(DryRunDescription),
+			mcp.DefaultBool(false),
+		),
+	)
+}
+
+// CreateRolloutResumeTool creates a tool for resuming a paused Deployment rollout
+func CreateRolloutResumeTool() mcp.Tool {
+	return mcp.NewTool("rollout_resume",
+		mcp.WithDescription("Resume the rollout of a paused Deployment"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Deployment name"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description(fmt.Sprintf("Namespace (default: %s)", k8s.DefaultNamespace)),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description(DryRunDescription),
+			mcp.DefaultBool(false),
+		),
+	)
+}
+
+// CreateRolloutUndoTool creates a tool for rolling back a Deployment
+func CreateRolloutUndoTool() mcp.Tool {
+	return mcp.NewTool("rollout_undo",
+		mcp.WithDescription("Roll back a Deployment to the pod template of a previous revision, like kubectl rollout undo"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Deployment name"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description(fmt.Sprintf("Namespace (default: %s)", k8s.DefaultNamespace)),
+		),
+		mcp.WithNumber("to_revision",
+			mcp.Description("Revision to roll back to (default: the previous revision)"),
+			mcp.Min(0),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description(DryRunDescription),
+			mcp.DefaultBool(false),
+		),
+	)
+}
+
+// getWorkloadType reads the optional kind parameter of a rollout tool request
+func getWorkloadType(request mcp.CallToolRequest) k8s.ResourceType {
+	return k8s.ResourceType{
+		Name:  request.GetString("kind", k8s.KindDeployment),
+		Group: "apps",
+	}
+}
+
+// HandleRolloutRestart handles the rollout restart tool
+func HandleRolloutRestart(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: name: %w", err)
+		}
+
+		namespace := request.GetString("namespace", k8s.DefaultNamespace)
+		dryRun := request.GetBool("dry_run", false)
+
+		workload, err := client.RestartRollout(ctx, getWorkloadType(request), name, namespace, dryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonResponse, err := json.Marshal(workload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}
+
+// HandleRolloutStatus handles the rollout status tool
+func HandleRolloutStatus(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: name: %w", err)
+		}
+
+		namespace := request.GetString("namespace", k8s.DefaultNamespace)
+
+		status, err := client.GetRolloutStatus(ctx, getWorkloadType(request), name, namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonResponse, err := json.Marshal(status)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}
+
+// HandleRolloutPause handles the rollout pause tool
+func HandleRolloutPause(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return handleRolloutPause(client, true)
+}
+
+// HandleRolloutResume handles the rollout resume tool
+func HandleRolloutResume(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return handleRolloutPause(client, false)
+}
+
+// handleRolloutPause pauses or resumes a Deployment rollout
+func handleRolloutPause(client *k8s.Client, paused bool) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: name: %w", err)
+		}
+
+		namespace := request.GetString("namespace", k8s.DefaultNamespace)
+		dryRun := request.GetBool("dry_run", false)
+
+		_, err = client.PauseRollout(ctx, name, namespace, paused, dryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		action := "resumed"
+		if paused {
+			action = "paused"
+		}
+		if dryRun {
+			return mcp.NewToolResultText(fmt.Sprintf("Dry run: deployment %s/%s would be %s", namespace, name, action)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Successfully %s deployment %s/%s", action, namespace, name)), nil
+	}
+}
+
+// HandleRolloutUndo handles the rollout undo tool
+func HandleRolloutUndo(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: name: %w", err)
+		}
+
+		namespace := request.GetString("namespace", k8s.DefaultNamespace)
+		toRevision := request.GetInt("to_revision", 0)
+		if toRevision < 0 {
+			return nil, fmt.Errorf("invalid to_revision value: %d, must not be negative", toRevision)
+		}
+		dryRun := request.GetBool("dry_run", false)
+
+		deployment, err := client.UndoRollout(ctx, name, namespace, int64(toRevision), dryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonResponse, err := json.Marshal(deployment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}
+
+// CreateRolloutHistoryTool creates a tool for listing the revisions of a workload
+func CreateRolloutHistoryTool() mcp.Tool {
+	return mcp.NewTool("rollout_history",
+		mcp.WithDescription("List the revisions of a Deployment, StatefulSet or DaemonSet with change-cause, images and creation time, optionally with a diff of the pod templates of two revisions"),
+		mcp.WithString("kind",
+			mcp.Description(WorkloadKindDescription),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Workload name"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description(fmt.Sprintf("Namespace (default: %s)", k8s.DefaultNamespace)),
+		),
+		mcp.WithNumber("diff_from",
+			mcp.Description("Revision to diff from, enables a unified diff of the pod templates"),
+			mcp.Min(0),
+		),
+		mcp.WithNumber("diff_to",
+			mcp.Description("Revision to diff to (default: the current revision)"),
+			mcp.Min(0),
+		),
+	)
+}
+
+// HandleRolloutHistory handles the rollout history tool
+func HandleRolloutHistory(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: name: %w", err)
+		}
+
+		namespace := request.GetString("namespace", k8s.DefaultNamespace)
+		diffFrom := request.GetInt("diff_from", 0)
+		diffTo := request.GetInt("diff_to", 0)
+		if diffFrom < 0 || diffTo < 0 {
+			return nil, fmt.Errorf("invalid revision: diff_from and diff_to must not be negative")
+		}
+		if diffTo > 0 && diffFrom == 0 {
+			return nil, fmt.Errorf("diff_to requires diff_from")
+		}
+
+		history, err := client.GetRolloutHistory(ctx, getWorkloadType(request), name, namespace, int64(diffFrom), int64(diffTo))
+		if err != nil {
+			return nil, err
+		}
+
+		jsonResponse, err := json.Marshal(history)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}
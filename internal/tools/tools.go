@@ -3,18 +3,55 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 	"github.com/silenceper/mcp-k8s/internal/k8s"
 )
 
 const (
 	// DefaultPodLogTailLinesStr is the default number of lines to retrieve from pod logs as string
 	DefaultPodLogTailLinesStr = "50"
+	// DefaultFollowSeconds is the default time get_pod_logs follows logs for
+	DefaultFollowSeconds = 30
+	// MaxFollowSeconds is the upper bound of the time get_pod_logs follows logs for
+	MaxFollowSeconds = 300
+	// DefaultFollowMaxLines is the default line budget when following logs
+	DefaultFollowMaxLines = 500
+	// DefaultListMaxResponseBytes is the default size limit of a list_resources response
+	DefaultListMaxResponseBytes = 256 * 1024
+	// listEnvelopeBytes is the room reserved for the list fields around the items
+	listEnvelopeBytes = 4096
+
+	// ResourceKindDescription describes the kind parameter shared by the resource tools
+	ResourceKindDescription = "Resource type: kind, plural name or short name, optionally qualified by group (e.g. Deployment, deployments, deploy, certificates.cert-manager.io)"
+	// ResourceAPIVersionDescription describes the apiVersion parameter shared by the resource tools
+	ResourceAPIVersionDescription = "API version of the resource type (e.g. apps/v1, or v1 for the core group), used when the kind exists in several groups or versions"
+	// ResourceGroupDescription describes the group parameter shared by the resource tools
+	ResourceGroupDescription = "API group of the resource type (e.g. apps, cert-manager.io), used when the kind exists in several groups"
+	// DryRunDescription describes the dry_run parameter shared by the mutating tools
+	DryRunDescription = "Preview the change with a server-side dry run: admission webhooks and validation run, but nothing is persisted"
 )
 
+// getResourceType reads the kind, apiVersion and group parameters of a resource tool request
+func getResourceType(request mcp.CallToolRequest) (k8s.ResourceType, error) {
+	kind, err := request.RequireString("kind")
+	if err != nil {
+		return k8s.ResourceType{}, fmt.Errorf("missing required parameter: kind: %w", err)
+	}
+
+	return k8s.ResourceType{
+		Name:       kind,
+		APIVersion: request.GetString("apiVersion", ""),
+		Group:      request.GetString("group", ""),
+	}, nil
+}
+
 // CreateGetAPIResourcesTool creates a tool for getting API resources
 func CreateGetAPIResourcesTool() mcp.Tool {
 	return mcp.NewTool("get_api_resources",
@@ -32,11 +69,17 @@ func CreateGetAPIResourcesTool() mcp.Tool {
 
 // CreateGetResourceTool creates a tool for getting a specific resource
 func CreateGetResourceTool() mcp.Tool {
-	return mcp.NewTool("get_resource",
+	options := []mcp.ToolOption{
 		mcp.WithDescription("Get detailed information about a specific resource"),
 		mcp.WithString("kind",
 			mcp.Required(),
-			mcp.Description("Resource type"),
+			mcp.Description(ResourceKindDescription),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description(ResourceAPIVersionDescription),
+		),
+		mcp.WithString("group",
+			mcp.Description(ResourceGroupDescription),
 		),
 		mcp.WithString("name",
 			mcp.Required(),
@@ -45,16 +88,25 @@ func CreateGetResourceTool() mcp.Tool {
 		mcp.WithString("namespace",
 			mcp.Description("Namespace (required for namespace-scoped resources)"),
 		),
-	)
+	}
+	options = append(options, withOutputOptions()...)
+
+	return mcp.NewTool("get_resource", options...)
 }
 
 // CreateListResourcesTool creates a tool for listing resources
 func CreateListResourcesTool() mcp.Tool {
-	return mcp.NewTool("list_resources",
-		mcp.WithDescription("List all instances of a resource type"),
+	options := []mcp.ToolOption{
+		mcp.WithDescription("List instances of a resource type. Large results are returned in pages, pass the returned continue token to fetch the next page. A page larger than the response size limit is truncated to the items that fit and marked truncated without a continue token, request the suggested limit to page through all of them"),
 		mcp.WithString("kind",
 			mcp.Required(),
-			mcp.Description("Resource type"),
+			mcp.Description(ResourceKindDescription),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description(ResourceAPIVersionDescription),
+		),
+		mcp.WithString("group",
+			mcp.Description(ResourceGroupDescription),
 		),
 		mcp.WithString("namespace",
 			mcp.Description("Namespace (only list resources in this namespace)"),
@@ -65,7 +117,21 @@ func CreateListResourcesTool() mcp.Tool {
 		mcp.WithString("fieldSelector",
 			mcp.Description("Field selector (format: key1=value1,key2=value2)"),
 		),
-	)
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of resources to return, a continue token is returned when more are available (default: no limit)"),
+		),
+		mcp.WithString("continue",
+			mcp.Description("Continue token returned by a previous list_resources call, to fetch the next page"),
+		),
+		mcp.WithString("output",
+			mcp.Description("Output format: json (full objects, default), table (the columns kubectl get shows) or wide (kubectl get -o wide). sanitize, keep, jsonpath and fields only apply to json output"),
+			mcp.Enum(OutputJSON, OutputTable, OutputWide),
+			mcp.DefaultString(OutputJSON),
+		),
+	}
+	options = append(options, withOutputOptions()...)
+
+	return mcp.NewTool("list_resources", options...)
 }
 
 // CreateCreateResourceTool creates a tool for creating resources
@@ -74,14 +140,24 @@ func CreateCreateResourceTool() mcp.Tool {
 		mcp.WithDescription("Create a new resource"),
 		mcp.WithString("kind",
 			mcp.Required(),
-			mcp.Description("Resource type"),
+			mcp.Description(ResourceKindDescription),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description(ResourceAPIVersionDescription),
+		),
+		mcp.WithString("group",
+			mcp.Description(ResourceGroupDescription),
 		),
 		mcp.WithString("namespace",
-			mcp.Description("Namespace (required for namespace-scoped resources)"),
+			mcp.Description("Namespace for a namespace-scoped resource that does not set metadata.namespace, must match metadata.namespace otherwise"),
 		),
 		mcp.WithString("manifest",
 			mcp.Required(),
-			mcp.Description("Resource manifest (Only JSON is supported)"),
+			mcp.Description("Resource manifest in YAML or JSON format"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description(DryRunDescription),
+			mcp.DefaultBool(false),
 		),
 	)
 }
@@ -92,18 +168,106 @@ func CreateUpdateResourceTool() mcp.Tool {
 		mcp.WithDescription("Update an existing resource"),
 		mcp.WithString("kind",
 			mcp.Required(),
-			mcp.Description("Resource type"),
+			mcp.Description(ResourceKindDescription),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description(ResourceAPIVersionDescription),
+		),
+		mcp.WithString("group",
+			mcp.Description(ResourceGroupDescription),
 		),
 		mcp.WithString("name",
 			mcp.Required(),
 			mcp.Description("Resource name"),
 		),
 		mcp.WithString("namespace",
-			mcp.Description("Namespace (required for namespace-scoped resources)"),
+			mcp.Description("Namespace for a namespace-scoped resource that does not set metadata.namespace, must match metadata.namespace otherwise"),
 		),
 		mcp.WithString("manifest",
 			mcp.Required(),
-			mcp.Description("Resource manifest (Only JSON is supported)"),
+			mcp.Description("Resource manifest in YAML or JSON format"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description(DryRunDescription),
+			mcp.DefaultBool(false),
+		),
+	)
+}
+
+// CreatePatchResourceTool creates a tool for patching resources
+func CreatePatchResourceTool() mcp.Tool {
+	return mcp.NewTool("patch_resource",
+		mcp.WithDescription("Patch an existing resource without sending the full object, e.g. to change replicas or an image"),
+		mcp.WithString("kind",
+			mcp.Required(),
+			mcp.Description(ResourceKindDescription),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description(ResourceAPIVersionDescription),
+		),
+		mcp.WithString("group",
+			mcp.Description(ResourceGroupDescription),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Resource name"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace (required for namespace-scoped resources)"),
+		),
+		mcp.WithString("patch_type",
+			mcp.Description("Patch type: json (RFC 6902 JSON Patch), merge (RFC 7386 JSON Merge Patch) or strategic (strategic merge patch, built-in types only)"),
+			mcp.Enum("json", "merge", "strategic"),
+			mcp.DefaultString("merge"),
+		),
+		mcp.WithString("patch",
+			mcp.Required(),
+			mcp.Description("Patch body (Only JSON is supported)"),
+		),
+		mcp.WithString("subresource",
+			mcp.Description("Subresource to patch instead of the main resource"),
+			mcp.Enum("status", "scale"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description(DryRunDescription),
+			mcp.DefaultBool(false),
+		),
+	)
+}
+
+// CreateScaleResourceTool creates a tool for scaling resources
+func CreateScaleResourceTool() mcp.Tool {
+	return mcp.NewTool("scale_resource",
+		mcp.WithDescription("Set the number of replicas of a Deployment, StatefulSet, ReplicaSet or any custom resource exposing the scale subresource"),
+		mcp.WithString("kind",
+			mcp.Required(),
+			mcp.Description(ResourceKindDescription),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description(ResourceAPIVersionDescription),
+		),
+		mcp.WithString("group",
+			mcp.Description(ResourceGroupDescription),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Resource name"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description(fmt.Sprintf("Namespace (default: %s)", k8s.DefaultNamespace)),
+		),
+		mcp.WithNumber("replicas",
+			mcp.Required(),
+			mcp.Description("Desired number of replicas"),
+			mcp.Min(0),
+		),
+		mcp.WithNumber("current_replicas",
+			mcp.Description("Only scale if the current number of replicas matches this value"),
+			mcp.Min(0),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description(DryRunDescription),
+			mcp.DefaultBool(false),
 		),
 	)
 }
@@ -114,7 +278,13 @@ func CreateDeleteResourceTool() mcp.Tool {
 		mcp.WithDescription("Delete a resource"),
 		mcp.WithString("kind",
 			mcp.Required(),
-			mcp.Description("Resource type"),
+			mcp.Description(ResourceKindDescription),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description(ResourceAPIVersionDescription),
+		),
+		mcp.WithString("group",
+			mcp.Description(ResourceGroupDescription),
 		),
 		mcp.WithString("name",
 			mcp.Required(),
@@ -123,6 +293,106 @@ func CreateDeleteResourceTool() mcp.Tool {
 		mcp.WithString("namespace",
 			mcp.Description("Namespace (required for namespace-scoped resources)"),
 		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description(DryRunDescription),
+			mcp.DefaultBool(false),
+		),
+	)
+}
+
+// CreateApplyResourceTool creates a tool for applying resources with server-side apply
+func CreateApplyResourceTool() mcp.Tool {
+	return mcp.NewTool("apply_resource",
+		mcp.WithDescription("Create or update a resource using server-side apply. Only the fields in the manifest are managed, and conflicts with fields owned by other managers are reported"),
+		mcp.WithString("kind",
+			mcp.Required(),
+			mcp.Description(ResourceKindDescription),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description(ResourceAPIVersionDescription),
+		),
+		mcp.WithString("group",
+			mcp.Description(ResourceGroupDescription),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace for a namespace-scoped resource that does not set metadata.namespace, must match metadata.namespace otherwise"),
+		),
+		mcp.WithString("manifest",
+			mcp.Required(),
+			mcp.Description("Resource manifest in YAML or JSON format"),
+		),
+		mcp.WithString("field_manager",
+			mcp.Description(fmt.Sprintf("Field manager name recorded as the owner of applied fields (default: %s)", k8s.DefaultFieldManager)),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Force the apply and take ownership of fields that conflict with other managers"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description(DryRunDescription),
+			mcp.DefaultBool(false),
+		),
+	)
+}
+
+// CreateApplyManifestsTool creates a tool for applying a multi-document manifest stream
+func CreateApplyManifestsTool() mcp.Tool {
+	return mcp.NewTool("apply_manifests",
+		mcp.WithDescription("Apply one or more resources from a YAML stream (documents separated by ---) or JSON using server-side apply. Each document's type is taken from its own apiVersion and kind, and a result is reported for every document in order"),
+		mcp.WithString("manifests",
+			mcp.Required(),
+			mcp.Description("Resource manifests in YAML or JSON format, YAML documents separated by ---"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace for namespace-scoped resources that do not set metadata.namespace, documents that set a different one fail. Cluster-scoped resources ignore it"),
+		),
+		mcp.WithString("field_manager",
+			mcp.Description(fmt.Sprintf("Field manager name recorded as the owner of applied fields (default: %s)", k8s.DefaultFieldManager)),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Force the apply and take ownership of fields that conflict with other managers"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description(DryRunDescription),
+			mcp.DefaultBool(false),
+		),
+	)
+}
+
+// CreateDiffResourceTool creates a tool for diffing a manifest against the live resource
+func CreateDiffResourceTool() mcp.Tool {
+	return mcp.NewTool("diff_resource",
+		mcp.WithDescription("Show what applying a manifest would change on the live resource. The result is computed with a server-side dry-run apply, nothing is persisted"),
+		mcp.WithString("kind",
+			mcp.Required(),
+			mcp.Description(ResourceKindDescription),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description(ResourceAPIVersionDescription),
+		),
+		mcp.WithString("group",
+			mcp.Description(ResourceGroupDescription),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace for a namespace-scoped resource that does not set metadata.namespace, must match metadata.namespace otherwise"),
+		),
+		mcp.WithString("manifest",
+			mcp.Required(),
+			mcp.Description("Resource manifest in YAML or JSON format"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Diff format: unified (unified diff of the YAML) or structured (list of changed field paths)"),
+			mcp.Enum(k8s.DiffFormatUnified, k8s.DiffFormatStructured),
+			mcp.DefaultString(k8s.DiffFormatUnified),
+		),
+		mcp.WithString("field_manager",
+			mcp.Description(fmt.Sprintf("Field manager name used for the dry-run apply (default: %s)", k8s.DefaultFieldManager)),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Take ownership of fields that conflict with other managers in the dry-run apply"),
+			mcp.DefaultBool(false),
+		),
 	)
 }
 
@@ -147,11 +417,11 @@ func HandleGetAPIResources(client *k8s.Client) func(ctx context.Context, request
 }
 
 // HandleGetResource handles the get resource tool
-func HandleGetResource(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func HandleGetResource(client *k8s.Client, outputConfig OutputConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		kind, err := request.RequireString("kind")
+		resourceType, err := getResourceType(request)
 		if err != nil {
-			return nil, fmt.Errorf("missing required parameter: kind: %w", err)
+			return nil, err
 		}
 
 		name, err := request.RequireString("name")
@@ -160,12 +430,32 @@ func HandleGetResource(client *k8s.Client) func(ctx context.Context, request mcp
 		}
 
 		namespace := request.GetString("namespace", "")
+		outputOptions, err := getOutputOptions(request, outputConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		resource, err := client.GetResource(ctx, resourceType, name, namespace)
+		if err != nil {
+			return nil, err
+		}
 
-		resource, err := client.GetResource(ctx, kind, name, namespace)
+		resource, err = shapeResource(resource, outputOptions)
 		if err != nil {
 			return nil, err
 		}
 
+		if outputOptions.jsonPath != "" {
+			result, err := evaluateJSONPath(outputOptions.jsonPath, resource)
+			if err != nil {
+				return nil, err
+			}
+			return mcp.NewToolResultText(result), nil
+		}
+		if len(outputOptions.fields) > 0 {
+			resource = projectFields(resource, outputOptions.fields)
+		}
+
 		jsonResponse, err := json.Marshal(resource)
 		if err != nil {
 			return nil, fmt.Errorf("failed to serialize response: %w", err)
@@ -175,38 +465,182 @@ func HandleGetResource(client *k8s.Client) func(ctx context.Context, request mcp
 	}
 }
 
-// HandleListResources handles the list resources tool
-func HandleListResources(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// HandleListResources handles the list resources tool. Responses larger than the configured limit
+// are truncated, together with the limit that pages through the resources within the size limit.
+func HandleListResources(client *k8s.Client, outputConfig OutputConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		kind, err := request.RequireString("kind")
+		resourceType, err := getResourceType(request)
 		if err != nil {
-			return nil, fmt.Errorf("missing required parameter: kind: %w", err)
+			return nil, err
 		}
 
 		namespace := request.GetString("namespace", "")
 		labelSelector := request.GetString("labelSelector", "")
 		fieldSelector := request.GetString("fieldSelector", "")
+		continueToken := request.GetString("continue", "")
+		limit := request.GetInt("limit", 0)
+		if limit < 0 {
+			return nil, fmt.Errorf("invalid limit value: %d, must not be negative", limit)
+		}
+		outputOptions, err := getOutputOptions(request, outputConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		switch output := request.GetString("output", OutputJSON); output {
+		case OutputJSON:
+		case OutputTable, OutputWide:
+			// Tables hold the columns computed by the API server rather than objects, so there is
+			// nothing to shape
+			if set := shapingParameters(request); len(set) > 0 {
+				return nil, fmt.Errorf("%s cannot be used with %s output", strings.Join(set, ", "), output)
+			}
+
+			table, err := client.ListResourcesTable(ctx, resourceType, namespace, labelSelector, fieldSelector, int64(limit), continueToken)
+			if err != nil {
+				return nil, err
+			}
+
+			showNamespace := table.Namespaced && namespace == ""
+			text, err := renderTableWithinBytes(table, output == OutputWide, showNamespace, outputConfig.ListMaxResponseBytes)
+			if err != nil {
+				return nil, err
+			}
+			return mcp.NewToolResultText(text), nil
+		default:
+			return nil, fmt.Errorf("invalid output value: %s, must be one of: %s, %s, %s", output, OutputJSON, OutputTable, OutputWide)
+		}
 
-		resources, err := client.ListResources(ctx, kind, namespace, labelSelector, fieldSelector)
+		resources, err := client.ListResources(ctx, resourceType, namespace, labelSelector, fieldSelector, int64(limit), continueToken)
 		if err != nil {
 			return nil, err
 		}
+		if resources.Items, err = shapeResources(resources.Items, outputOptions); err != nil {
+			return nil, err
+		}
+		if len(outputOptions.fields) > 0 {
+			resources.Items = projectResources(resources.Items, outputOptions.fields)
+		}
 
-		jsonResponse, err := json.Marshal(resources)
+		jsonResponse, err := marshalResourceList(resources, outputConfig.ListMaxResponseBytes)
 		if err != nil {
-			return nil, fmt.Errorf("failed to serialize response: %w", err)
+			return nil, err
+		}
+
+		if outputOptions.jsonPath != "" {
+			return listJSONPathResult(resources, outputOptions.jsonPath)
 		}
 
 		return mcp.NewToolResultText(string(jsonResponse)), nil
 	}
 }
 
+// jsonPathListResult is the result of a JSONPath evaluated against a page of resources
+type jsonPathListResult struct {
+	Result             string `json:"result"`
+	Continue           string `json:"continue,omitempty"`
+	RemainingItemCount *int64 `json:"remainingItemCount,omitempty"`
+	Truncated          bool   `json:"truncated,omitempty"`
+	SuggestedLimit     int    `json:"suggestedLimit,omitempty"`
+}
+
+// listJSONPathResult evaluates a JSONPath against a page of resources wrapped in a List object.
+// The plain text output is returned when the page is complete, otherwise it is returned together
+// with the continue token.
+func listJSONPathResult(resources *k8s.ResourceList, template string) (*mcp.CallToolResult, error) {
+	items := make([]interface{}, 0, len(resources.Items))
+	for _, item := range resources.Items {
+		items = append(items, item)
+	}
+
+	result, err := evaluateJSONPath(template, map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items":      items,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resources.Continue == "" && !resources.Truncated {
+		return mcp.NewToolResultText(result), nil
+	}
+
+	jsonResponse, err := json.Marshal(jsonPathListResult{
+		Result:             result,
+		Continue:           resources.Continue,
+		RemainingItemCount: resources.RemainingItemCount,
+		Truncated:          resources.Truncated,
+		SuggestedLimit:     resources.SuggestedLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize response: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
+// marshalResourceList serializes a page of resources. A page larger than maxBytes is truncated to
+// the leading items that fit, without a continue token, and the number of items that fit is
+// suggested as the limit to page with. A maxBytes of 0 disables the limit.
+func marshalResourceList(resources *k8s.ResourceList, maxBytes int) ([]byte, error) {
+	jsonResponse, err := json.Marshal(resources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize response: %w", err)
+	}
+	if maxBytes <= 0 || len(jsonResponse) <= maxBytes {
+		return jsonResponse, nil
+	}
+
+	fit := countItemsWithinBytes(resources.Items, maxBytes)
+	for {
+		if fit < len(resources.Items) {
+			resources.Items = resources.Items[:fit]
+			resources.Continue = ""
+			resources.RemainingItemCount = nil
+			resources.Truncated = true
+			resources.SuggestedLimit = fit
+		}
+
+		jsonResponse, err = json.Marshal(resources)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+		if len(jsonResponse) <= maxBytes {
+			return jsonResponse, nil
+		}
+		// The envelope took more room than reserved, e.g. for a long continue token
+		if fit <= 1 {
+			return nil, fmt.Errorf("a single resource exceeds the response size limit of %d bytes, use get_resource, fields or jsonpath to select less of it", maxBytes)
+		}
+		fit--
+	}
+}
+
+// countItemsWithinBytes returns how many leading items fit in maxBytes once serialized,
+// always at least one so that paging makes progress
+func countItemsWithinBytes(items []map[string]interface{}, maxBytes int) int {
+	// Reserve room for the list envelope and continue token
+	size := listEnvelopeBytes
+	for i, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return max(i, 1)
+		}
+		size += len(data) + 1
+		if size > maxBytes {
+			return max(i, 1)
+		}
+	}
+	return len(items)
+}
+
 // HandleCreateResource handles the create resource tool
 func HandleCreateResource(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		kind, err := request.RequireString("kind")
+		resourceType, err := getResourceType(request)
 		if err != nil {
-			return nil, fmt.Errorf("missing required parameter: kind: %w", err)
+			return nil, err
 		}
 
 		manifest, err := request.RequireString("manifest")
@@ -215,8 +649,9 @@ func HandleCreateResource(client *k8s.Client) func(ctx context.Context, request
 		}
 
 		namespace := request.GetString("namespace", "")
+		dryRun := request.GetBool("dry_run", false)
 
-		resource, err := client.CreateResource(ctx, kind, namespace, manifest)
+		resource, err := client.CreateResource(ctx, resourceType, namespace, manifest, dryRun)
 		if err != nil {
 			return nil, err
 		}
@@ -233,9 +668,9 @@ func HandleCreateResource(client *k8s.Client) func(ctx context.Context, request
 // HandleUpdateResource handles the update resource tool
 func HandleUpdateResource(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		kind, err := request.RequireString("kind")
+		resourceType, err := getResourceType(request)
 		if err != nil {
-			return nil, fmt.Errorf("missing required parameter: kind: %w", err)
+			return nil, err
 		}
 
 		name, err := request.RequireString("name")
@@ -249,8 +684,9 @@ func HandleUpdateResource(client *k8s.Client) func(ctx context.Context, request
 		}
 
 		namespace := request.GetString("namespace", "")
+		dryRun := request.GetBool("dry_run", false)
 
-		resource, err := client.UpdateResource(ctx, kind, name, namespace, manifest)
+		resource, err := client.UpdateResource(ctx, resourceType, name, namespace, manifest, dryRun)
 		if err != nil {
 			return nil, err
 		}
@@ -264,12 +700,93 @@ func HandleUpdateResource(client *k8s.Client) func(ctx context.Context, request
 	}
 }
 
+// HandlePatchResource handles the patch resource tool
+func HandlePatchResource(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resourceType, err := getResourceType(request)
+		if err != nil {
+			return nil, err
+		}
+
+		name, err := request.RequireString("name")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: name: %w", err)
+		}
+
+		patch, err := request.RequireString("patch")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: patch: %w", err)
+		}
+
+		namespace := request.GetString("namespace", "")
+		patchType := request.GetString("patch_type", "merge")
+		subresource := request.GetString("subresource", "")
+		dryRun := request.GetBool("dry_run", false)
+
+		resource, err := client.PatchResource(ctx, resourceType, name, namespace, patchType, patch, subresource, dryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonResponse, err := json.Marshal(resource)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}
+
+// HandleScaleResource handles the scale resource tool
+func HandleScaleResource(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resourceType, err := getResourceType(request)
+		if err != nil {
+			return nil, err
+		}
+
+		name, err := request.RequireString("name")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: name: %w", err)
+		}
+
+		replicas, err := request.RequireInt("replicas")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: replicas: %w", err)
+		}
+
+		namespace := request.GetString("namespace", k8s.DefaultNamespace)
+		dryRun := request.GetBool("dry_run", false)
+
+		var currentReplicas *int64
+		if _, ok := request.GetArguments()["current_replicas"]; ok {
+			current, err := request.RequireInt("current_replicas")
+			if err != nil {
+				return nil, fmt.Errorf("invalid current_replicas value: %w", err)
+			}
+			currentReplicas = int64Ptr(int64(current))
+		}
+
+		scale, err := client.ScaleResource(ctx, resourceType, name, namespace, int64(replicas), currentReplicas, dryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonResponse, err := json.Marshal(scale)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}
+
 // HandleDeleteResource handles the delete resource tool
 func HandleDeleteResource(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		kind, err := request.RequireString("kind")
+		resourceType, err := getResourceType(request)
 		if err != nil {
-			return nil, fmt.Errorf("missing required parameter: kind: %w", err)
+			return nil, err
 		}
 
 		name, err := request.RequireString("name")
@@ -278,20 +795,132 @@ func HandleDeleteResource(client *k8s.Client) func(ctx context.Context, request
 		}
 
 		namespace := request.GetString("namespace", "")
+		dryRun := request.GetBool("dry_run", false)
+
+		err = client.DeleteResource(ctx, resourceType, name, namespace, dryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		if dryRun {
+			return mcp.NewToolResultText(fmt.Sprintf("Dry run: resource %s/%s would be deleted", resourceType.Name, name)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Successfully deleted resource %s/%s", resourceType.Name, name)), nil
+	}
+}
+
+// HandleApplyResource handles the apply resource tool
+func HandleApplyResource(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resourceType, err := getResourceType(request)
+		if err != nil {
+			return nil, err
+		}
+
+		manifest, err := request.RequireString("manifest")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: manifest: %w", err)
+		}
+
+		namespace := request.GetString("namespace", "")
+		fieldManager := request.GetString("field_manager", k8s.DefaultFieldManager)
+		force := request.GetBool("force", false)
+		dryRun := request.GetBool("dry_run", false)
+
+		resource, err := client.ApplyResource(ctx, resourceType, namespace, manifest, fieldManager, force, dryRun)
+		if err != nil {
+			// Report field conflicts in a structured way so the caller can decide whether to force
+			var conflictErr *k8s.ApplyConflictError
+			if errors.As(err, &conflictErr) {
+				jsonResponse, marshalErr := json.Marshal(conflictErr)
+				if marshalErr != nil {
+					return nil, fmt.Errorf("failed to serialize response: %w", marshalErr)
+				}
+				return mcp.NewToolResultError(string(jsonResponse)), nil
+			}
+			return nil, err
+		}
+
+		jsonResponse, err := json.Marshal(resource)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}
+
+// HandleApplyManifests handles the apply manifests tool
+func HandleApplyManifests(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		manifests, err := request.RequireString("manifests")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: manifests: %w", err)
+		}
+
+		namespace := request.GetString("namespace", "")
+		fieldManager := request.GetString("field_manager", k8s.DefaultFieldManager)
+		force := request.GetBool("force", false)
+		dryRun := request.GetBool("dry_run", false)
+
+		results, err := client.ApplyManifests(ctx, namespace, manifests, fieldManager, force, dryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonResponse, err := json.Marshal(results)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}
+
+// HandleDiffResource handles the diff resource tool
+func HandleDiffResource(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resourceType, err := getResourceType(request)
+		if err != nil {
+			return nil, err
+		}
+
+		manifest, err := request.RequireString("manifest")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: manifest: %w", err)
+		}
+
+		namespace := request.GetString("namespace", "")
+		format := request.GetString("format", k8s.DiffFormatUnified)
+		fieldManager := request.GetString("field_manager", k8s.DefaultFieldManager)
+		force := request.GetBool("force", false)
 
-		err = client.DeleteResource(ctx, kind, name, namespace)
+		diff, err := client.DiffResource(ctx, resourceType, namespace, manifest, fieldManager, format, force)
 		if err != nil {
 			return nil, err
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("Successfully deleted resource %s/%s", kind, name)), nil
+		if format == k8s.DiffFormatUnified {
+			if !diff.Changed {
+				return mcp.NewToolResultText(fmt.Sprintf("No changes for resource %s/%s", resourceType.Name, diff.Name)), nil
+			}
+			return mcp.NewToolResultText(diff.Diff), nil
+		}
+
+		jsonResponse, err := json.Marshal(diff)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
 	}
 }
 
 // CreateGetPodLogsTool creates a tool for getting pod logs
 func CreateGetPodLogsTool() mcp.Tool {
-	return mcp.NewTool("get_pod_logs",
-		mcp.WithDescription("Retrieve logs from a specific pod"),
+	options := []mcp.ToolOption{
+		mcp.WithDescription("Retrieve logs from a specific pod, including the previous instance of a crash-looping container"),
 		mcp.WithString("pod_name",
 			mcp.Required(),
 			mcp.Description("Pod name"),
@@ -299,13 +928,92 @@ func CreateGetPodLogsTool() mcp.Tool {
 		mcp.WithString("namespace",
 			mcp.Description(fmt.Sprintf("Namespace (default: %s)", k8s.DefaultNamespace)),
 		),
+	}
+	options = append(options, withPodLogOptions()...)
+	options = append(options,
+		mcp.WithBoolean("follow",
+			mcp.Description("Stream new log lines for a bounded time, pushing each line to the client as a progress notification (or a log message notification if no progress token is given); the collected lines are also returned at the end (default: false)"),
+		),
+		mcp.WithNumber("follow_seconds",
+			mcp.Description(fmt.Sprintf("How long to follow the logs in seconds (default: %d, max: %d)", DefaultFollowSeconds, MaxFollowSeconds)),
+			mcp.Min(1),
+			mcp.Max(MaxFollowSeconds),
+		),
+		mcp.WithNumber("max_lines",
+			mcp.Description(fmt.Sprintf("Stop following after this many lines (default: %d)", DefaultFollowMaxLines)),
+			mcp.Min(1),
+		),
+	)
+
+	return mcp.NewTool("get_pod_logs", options...)
+}
+
+// CreateGetWorkloadLogsTool creates a tool for getting the logs of all pods of a workload
+func CreateGetWorkloadLogsTool() mcp.Tool {
+	options := []mcp.ToolOption{
+		mcp.WithDescription("Retrieve the logs of all pods of a Deployment, StatefulSet, DaemonSet, ReplicaSet or Job, or of the pods matching a label selector, interleaved by timestamp with a pod/container prefix"),
+		mcp.WithString("kind",
+			mcp.Description("Workload kind: Deployment, StatefulSet, DaemonSet, ReplicaSet or Job (requires name)"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Workload name (requires kind)"),
+		),
+		mcp.WithString("label_selector",
+			mcp.Description("Label selector of the pods, used instead of kind and name (format: key1=value1,key2=value2)"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description(fmt.Sprintf("Namespace (default: %s)", k8s.DefaultNamespace)),
+		),
+		mcp.WithNumber("max_pods",
+			mcp.Description(fmt.Sprintf("Maximum number of pods to read logs from, in pod name order (default: %d)", k8s.DefaultWorkloadLogMaxPods)),
+			mcp.Min(1),
+		),
+	}
+	options = append(options, withPodLogOptions()...)
+
+	return mcp.NewTool("get_workload_logs", options...)
+}
+
+// withPodLogOptions adds the parameters that select which logs are retrieved from a pod
+func withPodLogOptions() []mcp.ToolOption {
+	return []mcp.ToolOption{
 		mcp.WithString("container",
 			mcp.Description("Container name (optional, defaults to first container in multi-container pods)"),
 		),
 		mcp.WithString("tail_lines",
-			mcp.Description(fmt.Sprintf("Number of lines to retrieve from the end of logs (optional, default: %d)", k8s.DefaultPodLogTailLines)),
+			mcp.Description(fmt.Sprintf("Number of lines to retrieve from the end of logs per container, -1 retrieves all lines (optional, default: %d)", k8s.DefaultPodLogTailLines)),
 		),
-	)
+		mcp.WithBoolean("previous",
+			mcp.Description("Retrieve the logs of the previous terminated instance of the container, e.g. to diagnose CrashLoopBackOff (default: false)"),
+		),
+		mcp.WithNumber("since_seconds",
+			mcp.Description("Only return logs newer than this many seconds (optional)"),
+			mcp.Min(1),
+		),
+		mcp.WithString("since_time",
+			mcp.Description("Only return logs after this RFC3339 time, e.g. 2024-01-02T15:04:05Z (optional, cannot be combined with since_seconds)"),
+		),
+		mcp.WithBoolean("timestamps",
+			mcp.Description("Prefix every line with its RFC3339 timestamp (default: false)"),
+		),
+		mcp.WithNumber("limit_bytes",
+			mcp.Description("Maximum number of bytes of logs to return per container (optional)"),
+			mcp.Min(1),
+		),
+		mcp.WithBoolean("all_containers",
+			mcp.Description("Retrieve the logs of all containers of the pod, each line prefixed with the container name; container is ignored (default: false)"),
+		),
+		mcp.WithString("grep",
+			mcp.Description("Regular expression (RE2 syntax) selecting the returned lines, applied on the server before the logs are returned (optional)"),
+		),
+		mcp.WithBoolean("invert",
+			mcp.Description("Return the lines not matching grep instead (default: false)"),
+		),
+		mcp.WithNumber("context_lines",
+			mcp.Description("Number of lines to return before and after every line selected by grep (default: 0)"),
+			mcp.Min(0),
+		),
+	}
 }
 
 // HandleGetPodLogs handles the get pod logs tool
@@ -317,15 +1025,17 @@ func HandleGetPodLogs(client *k8s.Client) func(ctx context.Context, request mcp.
 		}
 
 		namespace := request.GetString("namespace", k8s.DefaultNamespace)
-		container := request.GetString("container", "")
 
-		// Parse tail_lines as string and convert to int
-		tailLinesStr := request.GetString("tail_lines", DefaultPodLogTailLinesStr)
-		tailLines, err := strconv.Atoi(tailLinesStr)
+		opts, err := getPodLogOptions(request)
 		if err != nil {
-			return nil, fmt.Errorf("invalid tail_lines value: %s, must be a number", tailLinesStr)
+			return nil, err
+		}
+
+		if request.GetBool("follow", false) {
+			return followPodLogs(ctx, client, request, namespace, podName, opts)
 		}
-		logs, err := client.GetPodLogs(ctx, namespace, podName, container, tailLines)
+
+		logs, err := client.GetPodLogs(ctx, namespace, podName, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -334,6 +1044,147 @@ func HandleGetPodLogs(client *k8s.Client) func(ctx context.Context, request mcp.
 	}
 }
 
+// followPodLogs streams new log lines of a pod to the client and returns the collected lines
+func followPodLogs(ctx context.Context, client *k8s.Client, request mcp.CallToolRequest, namespace, podName string, opts k8s.PodLogOptions) (*mcp.CallToolResult, error) {
+	followSeconds := request.GetInt("follow_seconds", DefaultFollowSeconds)
+	if followSeconds <= 0 || followSeconds > MaxFollowSeconds {
+		return nil, fmt.Errorf("invalid follow_seconds value: %d, must be between 1 and %d", followSeconds, MaxFollowSeconds)
+	}
+	maxLines := request.GetInt("max_lines", DefaultFollowMaxLines)
+	if maxLines <= 0 {
+		return nil, fmt.Errorf("invalid max_lines value: %d, must be positive", maxLines)
+	}
+
+	var logs strings.Builder
+	count := 0
+	reason, err := client.FollowPodLogs(ctx, namespace, podName, opts, time.Duration(followSeconds)*time.Second, maxLines, func(line string) {
+		count++
+		logs.WriteString(line)
+		logs.WriteString("\n")
+		notifyLogLine(ctx, request, "get_pod_logs", line, count, maxLines)
+	}, func() {
+		// Separators between grep context groups are not log lines and do not count
+		logs.WriteString("--\n")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(&logs, "\nStopped following logs after %d lines: %s\n", count, reason)
+	return mcp.NewToolResultText(logs.String()), nil
+}
+
+// notifyLogLine pushes a log line to the client while a tool call is running. Clients that asked for
+// progress get a progress notification carrying the line, other clients a log message notification.
+func notifyLogLine(ctx context.Context, request mcp.CallToolRequest, logger, line string, count, total int) {
+	mcpServer := server.ServerFromContext(ctx)
+	if mcpServer == nil {
+		return
+	}
+
+	// Notifications are best effort, the collected lines are returned with the result anyway
+	if request.Params.Meta != nil && request.Params.Meta.ProgressToken != nil {
+		_ = mcpServer.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+			"progressToken": request.Params.Meta.ProgressToken,
+			"progress":      count,
+			"total":         total,
+			"message":       line,
+		})
+		return
+	}
+	_ = mcpServer.SendNotificationToClient(ctx, "notifications/message", map[string]any{
+		"level":  mcp.LoggingLevelInfo,
+		"logger": logger,
+		"data":   line,
+	})
+}
+
+// HandleGetWorkloadLogs handles the get workload logs tool
+func HandleGetWorkloadLogs(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		kind := request.GetString("kind", "")
+		name := request.GetString("name", "")
+		labelSelector := request.GetString("label_selector", "")
+		if (kind == "") != (name == "") {
+			return nil, fmt.Errorf("kind and name must be used together")
+		}
+		if kind == "" && labelSelector == "" {
+			return nil, fmt.Errorf("either kind and name or label_selector is required")
+		}
+
+		namespace := request.GetString("namespace", k8s.DefaultNamespace)
+		maxPods := request.GetInt("max_pods", k8s.DefaultWorkloadLogMaxPods)
+		if maxPods <= 0 {
+			return nil, fmt.Errorf("invalid max_pods value: %d, must be positive", maxPods)
+		}
+
+		opts, err := getPodLogOptions(request)
+		if err != nil {
+			return nil, err
+		}
+
+		logs, err := client.GetWorkloadLogs(ctx, k8s.ResourceType{Name: kind}, name, namespace, labelSelector, opts, maxPods, k8s.DefaultWorkloadLogConcurrency)
+		if err != nil {
+			return nil, err
+		}
+
+		return mcp.NewToolResultText(logs), nil
+	}
+}
+
+// getPodLogOptions reads the log selection parameters of a request
+func getPodLogOptions(request mcp.CallToolRequest) (k8s.PodLogOptions, error) {
+	opts := k8s.PodLogOptions{
+		Container:     request.GetString("container", ""),
+		AllContainers: request.GetBool("all_containers", false),
+		Previous:      request.GetBool("previous", false),
+		Timestamps:    request.GetBool("timestamps", false),
+	}
+
+	// Parse tail_lines as string and convert to int
+	tailLinesStr := request.GetString("tail_lines", DefaultPodLogTailLinesStr)
+	tailLines, err := strconv.ParseInt(tailLinesStr, 10, 64)
+	if err != nil {
+		return opts, fmt.Errorf("invalid tail_lines value: %s, must be a number", tailLinesStr)
+	}
+	opts.TailLines = tailLines
+
+	sinceSeconds := request.GetInt("since_seconds", 0)
+	if sinceSeconds < 0 {
+		return opts, fmt.Errorf("invalid since_seconds value: %d, must be positive", sinceSeconds)
+	}
+	opts.SinceSeconds = int64(sinceSeconds)
+
+	if sinceTimeStr := request.GetString("since_time", ""); sinceTimeStr != "" {
+		sinceTime, err := time.Parse(time.RFC3339, sinceTimeStr)
+		if err != nil {
+			return opts, fmt.Errorf("invalid since_time value: %s, must be an RFC3339 time", sinceTimeStr)
+		}
+		opts.SinceTime = &sinceTime
+	}
+	if opts.SinceSeconds > 0 && opts.SinceTime != nil {
+		return opts, fmt.Errorf("since_seconds and since_time cannot be used together")
+	}
+
+	limitBytes := request.GetInt("limit_bytes", 0)
+	if limitBytes < 0 {
+		return opts, fmt.Errorf("invalid limit_bytes value: %d, must be positive", limitBytes)
+	}
+	opts.LimitBytes = int64(limitBytes)
+
+	opts.Grep = request.GetString("grep", "")
+	opts.Invert = request.GetBool("invert", false)
+	opts.ContextLines = request.GetInt("context_lines", 0)
+	if opts.ContextLines < 0 {
+		return opts, fmt.Errorf("invalid context_lines value: %d, must not be negative", opts.ContextLines)
+	}
+	if opts.Grep == "" && (opts.Invert || opts.ContextLines > 0) {
+		return opts, fmt.Errorf("invert and context_lines require grep")
+	}
+
+	return opts, nil
+}
+
 // CreateListEventsTool creates a tool for listing events
 func CreateListEventsTool() mcp.Tool {
 	return mcp.NewTool("list_events",
@@ -374,3 +1225,8 @@ func HandleListEvents(client *k8s.Client) func(ctx context.Context, request mcp.
 		return mcp.NewToolResultText(string(jsonResponse)), nil
 	}
 }
+
+// int64Ptr returns a pointer to an int64
+func int64Ptr(i int64) *int64 {
+	return &i
+}
@@ -0,0 +1,274 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/silenceper/mcp-k8s/internal/k8s"
+)
+
+// testItems returns count resources whose serialized size is about size bytes each
+func testItems(count, size int) []map[string]interface{} {
+	items := make([]map[string]interface{}, count)
+	for i := range items {
+		items[i] = map[string]interface{}{
+			"metadata": map[string]interface{}{"name": fmt.Sprintf("item-%03d", i)},
+			"data":     strings.Repeat("x", size),
+		}
+	}
+	return items
+}
+
+func TestCountItemsWithinBytes(t *testing.T) {
+	items := testItems(10, 1000)
+	itemBytes, _ := json.Marshal(items[0])
+	perItem := len(itemBytes) + 1
+
+	tests := []struct {
+		name     string
+		items    []map[string]interface{}
+		maxBytes int
+		want     int
+	}{
+		{"all fit", items, listEnvelopeBytes + 10*perItem, 10},
+		{"some fit", items, listEnvelopeBytes + 3*perItem, 3},
+		{"just short of one more", items, listEnvelopeBytes + 4*perItem - 1, 3},
+		{"none fit returns one", items, listEnvelopeBytes, 1},
+		{"empty", nil, listEnvelopeBytes, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countItemsWithinBytes(tt.items, tt.maxBytes); got != tt.want {
+				t.Errorf("countItemsWithinBytes() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalResourceList(t *testing.T) {
+	t.Run("within limit", func(t *testing.T) {
+		resources := &k8s.ResourceList{Items: testItems(3, 100), Continue: "token"}
+		data, err := marshalResourceList(resources, 64*1024)
+		if err != nil {
+			t.Fatalf("marshalResourceList returned error: %v", err)
+		}
+		if resources.Truncated || resources.Continue != "token" || len(resources.Items) != 3 {
+			t.Errorf("page within the limit was changed: %+v", resources)
+		}
+		if !strings.Contains(string(data), `"continue":"token"`) {
+			t.Errorf("response %s lacks the continue token", data)
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		remaining := int64(40)
+		resources := &k8s.ResourceList{Items: testItems(20, 1000), Continue: "token", RemainingItemCount: &remaining}
+		maxBytes := listEnvelopeBytes + 5*1100
+		data, err := marshalResourceList(resources, maxBytes)
+		if err != nil {
+			t.Fatalf("marshalResourceList returned error: %v", err)
+		}
+		if len(data) > maxBytes {
+			t.Errorf("response is %d bytes, over the limit of %d", len(data), maxBytes)
+		}
+		if !resources.Truncated || resources.Continue != "" || resources.RemainingItemCount != nil {
+			t.Errorf("truncated page = truncated %v, continue %q, remaining %v, want truncated without continue token",
+				resources.Truncated, resources.Continue, resources.RemainingItemCount)
+		}
+		if resources.SuggestedLimit != len(resources.Items) || len(resources.Items) == 0 {
+			t.Errorf("suggested limit = %d for %d items", resources.SuggestedLimit, len(resources.Items))
+		}
+		var decoded k8s.ResourceList
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("invalid response: %v", err)
+		}
+		if !decoded.Truncated || decoded.SuggestedLimit != len(resources.Items) {
+			t.Errorf("response does not report the truncation: %+v", decoded)
+		}
+	})
+
+	t.Run("envelope over reserve", func(t *testing.T) {
+		// A continue token longer than the reserved envelope, so the counted items do not fit
+		resources := &k8s.ResourceList{Items: testItems(3, 1000), Continue: strings.Repeat("t", 2*listEnvelopeBytes)}
+		maxBytes := listEnvelopeBytes + 3*1100
+		data, err := marshalResourceList(resources, maxBytes)
+		if err != nil {
+			t.Fatalf("marshalResourceList returned error: %v", err)
+		}
+		if len(data) > maxBytes || !resources.Truncated {
+			t.Errorf("response is %d bytes (limit %d), truncated %v", len(data), maxBytes, resources.Truncated)
+		}
+	})
+
+	t.Run("single item over limit", func(t *testing.T) {
+		resources := &k8s.ResourceList{Items: testItems(2, 2*listEnvelopeBytes)}
+		if _, err := marshalResourceList(resources, listEnvelopeBytes); err == nil {
+			t.Fatal("marshalResourceList succeeded, want an error for an item over the limit")
+		}
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		resources := &k8s.ResourceList{Items: testItems(20, 1000)}
+		if _, err := marshalResourceList(resources, 0); err != nil || resources.Truncated {
+			t.Errorf("marshalResourceList without limit = truncated %v, error %v", resources.Truncated, err)
+		}
+	})
+}
+
+func TestRenderTableWithinBytes(t *testing.T) {
+	table := &k8s.ResourceTable{
+		Columns:  []k8s.TableColumn{{Name: "Name"}, {Name: "Status"}},
+		Continue: "token",
+	}
+	for i := 0; i < 100; i++ {
+		table.Rows = append(table.Rows, k8s.TableRow{Cells: []interface{}{fmt.Sprintf("pod-%03d", i), "Running"}})
+	}
+
+	full, err := renderTableWithinBytes(table, false, false, 0)
+	if err != nil || !strings.Contains(full, "continue token: token") {
+		t.Fatalf("renderTableWithinBytes without limit = %q, %v", full, err)
+	}
+
+	maxBytes := len(full) / 2
+	text, err := renderTableWithinBytes(table, false, false, maxBytes)
+	if err != nil {
+		t.Fatalf("renderTableWithinBytes returned error: %v", err)
+	}
+	if len(text) > maxBytes {
+		t.Errorf("table is %d bytes, over the limit of %d", len(text), maxBytes)
+	}
+	if strings.Contains(text, "continue token") {
+		t.Error("truncated table has a continue token")
+	}
+	if !strings.Contains(text, "Truncated to ") || !strings.Contains(text, "of 100 rows") {
+		t.Errorf("truncated table lacks the truncation note: %q", text[len(text)-120:])
+	}
+	if strings.Contains(text, "pod-099") || !strings.Contains(text, "pod-000") {
+		t.Error("truncated table does not keep the leading rows")
+	}
+
+	if _, err := renderTableWithinBytes(table, false, false, 10); err == nil {
+		t.Error("renderTableWithinBytes succeeded with a limit below a single row")
+	}
+}
+
+func TestHandleListResourcesRejectsShapingForTables(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		want      string
+	}{
+		{"sanitize", map[string]interface{}{"sanitize": false}, "sanitize cannot be used with table output"},
+		{"keep", map[string]interface{}{"keep": "spec"}, "keep cannot be used with table output"},
+		{"jsonpath", map[string]interface{}{"jsonpath": "{.items[*].metadata.name}"}, "jsonpath cannot be used with table output"},
+		{"keep and fields", map[string]interface{}{"keep": "status", "fields": "spec.replicas"}, "keep, fields cannot be used with table output"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arguments := map[string]interface{}{"kind": "pods", "output": OutputTable}
+			for name, value := range tt.arguments {
+				arguments[name] = value
+			}
+			var request mcp.CallToolRequest
+			request.Params.Arguments = arguments
+
+			// The options are validated before the client is used
+			_, err := HandleListResources(nil, OutputConfig{Sanitize: true})(context.Background(), request)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestShapingParameters(t *testing.T) {
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]interface{}{
+		"kind":     "pods",
+		"fields":   "spec.replicas",
+		"keep":     " ",
+		"sanitize": true,
+		"jsonpath": nil,
+	}
+
+	got := shapingParameters(request)
+	want := []string{"sanitize", "fields"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("shapingParameters = %v, want %v", got, want)
+	}
+}
+
+func TestGetPodLogOptionsSince(t *testing.T) {
+	sinceTime := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		arguments        map[string]interface{}
+		wantSinceSeconds int64
+		wantSinceTime    *time.Time
+		wantErr          string
+	}{
+		{
+			name:      "no limit",
+			arguments: map[string]interface{}{},
+		},
+		{
+			name:             "since seconds",
+			arguments:        map[string]interface{}{"since_seconds": 300},
+			wantSinceSeconds: 300,
+		},
+		{
+			name:          "since time",
+			arguments:     map[string]interface{}{"since_time": "2024-01-02T15:04:05Z"},
+			wantSinceTime: &sinceTime,
+		},
+		{
+			name:      "negative since seconds",
+			arguments: map[string]interface{}{"since_seconds": -1},
+			wantErr:   "invalid since_seconds value: -1, must be positive",
+		},
+		{
+			name:      "invalid since time",
+			arguments: map[string]interface{}{"since_time": "yesterday"},
+			wantErr:   "invalid since_time value: yesterday, must be an RFC3339 time",
+		},
+		{
+			name:      "since seconds and since time",
+			arguments: map[string]interface{}{"since_seconds": 300, "since_time": "2024-01-02T15:04:05Z"},
+			wantErr:   "since_seconds and since_time cannot be used together",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request mcp.CallToolRequest
+			request.Params.Arguments = tt.arguments
+
+			opts, err := getPodLogOptions(request)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("getPodLogOptions error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getPodLogOptions returned error: %v", err)
+			}
+
+			if opts.SinceSeconds != tt.wantSinceSeconds {
+				t.Errorf("since seconds = %d, want %d", opts.SinceSeconds, tt.wantSinceSeconds)
+			}
+			if (opts.SinceTime == nil) != (tt.wantSinceTime == nil) ||
+				(opts.SinceTime != nil && !opts.SinceTime.Equal(*tt.wantSinceTime)) {
+				t.Errorf("since time = %v, want %v", opts.SinceTime, tt.wantSinceTime)
+			}
+		})
+	}
+}
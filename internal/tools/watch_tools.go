@@ -0,0 +1,188 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/silenceper/mcp-k8s/internal/k8s"
+)
+
+const (
+	// DefaultWatchSeconds is the default time watch_resources collects events for
+	DefaultWatchSeconds = 30
+	// MaxWatchSeconds is the upper bound of the time watch_resources collects events for
+	MaxWatchSeconds = 600
+
+	// ConditionDescription describes the condition syntax shared by the watch tools
+	ConditionDescription = "Condition in kubectl wait --for syntax: delete, condition=Available (status True), condition=Ready=False, or jsonpath={.status.phase}=Running. The condition= prefix may be omitted, e.g. Ready or Available=True"
+)
+
+// CreateWatchResourcesTool creates a tool for watching resource changes over a time window
+func CreateWatchResourcesTool() mcp.Tool {
+	return mcp.NewTool("watch_resources",
+		mcp.WithDescription("Watch instances of a resource type for a time window and return the ADDED, MODIFIED and DELETED events as a compact change log with the changed field paths, optionally stopping once a resource meets a condition"),
+		mcp.WithString("kind",
+			mcp.Required(),
+			mcp.Description(ResourceKindDescription),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description(ResourceAPIVersionDescription),
+		),
+		mcp.WithString("group",
+			mcp.Description(ResourceGroupDescription),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace (only watch resources in this namespace)"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Only watch the resource with this name (optional)"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("Label selector (format: key1=value1,key2=value2)"),
+		),
+		mcp.WithString("fieldSelector",
+			mcp.Description("Field selector (format: key1=value1,key2=value2)"),
+		),
+		mcp.WithNumber("seconds",
+			mcp.Description(fmt.Sprintf("How long to watch in seconds (default: %d, max: %d)", DefaultWatchSeconds, MaxWatchSeconds)),
+			mcp.Min(1),
+			mcp.Max(MaxWatchSeconds),
+		),
+		mcp.WithString("until",
+			mcp.Description("Stop as soon as a watched resource meets this condition (optional). "+ConditionDescription),
+		),
+		mcp.WithNumber("max_events",
+			mcp.Description(fmt.Sprintf("Stop after this many events (default: %d)", k8s.DefaultWatchMaxEvents)),
+			mcp.Min(1),
+		),
+	)
+}
+
+// HandleWatchResources handles the watch resources tool
+func HandleWatchResources(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resourceType, err := getResourceType(request)
+		if err != nil {
+			return nil, err
+		}
+
+		namespace := request.GetString("namespace", "")
+		labelSelector := request.GetString("labelSelector", "")
+		fieldSelector := request.GetString("fieldSelector", "")
+		if name := request.GetString("name", ""); name != "" {
+			nameSelector := "metadata.name=" + name
+			if fieldSelector != "" {
+				fieldSelector = fieldSelector + "," + nameSelector
+			} else {
+				fieldSelector = nameSelector
+			}
+		}
+
+		seconds := request.GetInt("seconds", DefaultWatchSeconds)
+		if seconds <= 0 || seconds > MaxWatchSeconds {
+			return nil, fmt.Errorf("invalid seconds value: %d, must be between 1 and %d", seconds, MaxWatchSeconds)
+		}
+		maxEvents := request.GetInt("max_events", k8s.DefaultWatchMaxEvents)
+		if maxEvents <= 0 {
+			return nil, fmt.Errorf("invalid max_events value: %d, must be positive", maxEvents)
+		}
+
+		var until *k8s.Condition
+		if expression := request.GetString("until", ""); expression != "" {
+			until, err = k8s.ParseCondition(expression)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		result, err := client.WatchResources(ctx, resourceType, namespace, labelSelector, fieldSelector, time.Duration(seconds)*time.Second, until, maxEvents)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonResponse, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}
+
+// CreateWaitForConditionTool creates a tool for waiting until a resource meets a condition
+func CreateWaitForConditionTool() mcp.Tool {
+	return mcp.NewTool("wait_for_condition",
+		mcp.WithDescription("Wait until a resource meets a condition, like kubectl wait, e.g. a Deployment becoming Available, a Pod becoming Ready or a resource being deleted. Returns as soon as the condition is met or the timeout elapses"),
+		mcp.WithString("kind",
+			mcp.Required(),
+			mcp.Description(ResourceKindDescription),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description(ResourceAPIVersionDescription),
+		),
+		mcp.WithString("group",
+			mcp.Description(ResourceGroupDescription),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Resource name"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace (required for namespace-scoped resources)"),
+		),
+		mcp.WithString("condition",
+			mcp.Required(),
+			mcp.Description(ConditionDescription),
+		),
+		mcp.WithNumber("timeout_seconds",
+			mcp.Description(fmt.Sprintf("How long to wait in seconds (default: %d, max: %d)", int(k8s.DefaultWaitTimeout.Seconds()), MaxWatchSeconds)),
+			mcp.Min(1),
+			mcp.Max(MaxWatchSeconds),
+		),
+	)
+}
+
+// HandleWaitForCondition handles the wait for condition tool
+func HandleWaitForCondition(client *k8s.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resourceType, err := getResourceType(request)
+		if err != nil {
+			return nil, err
+		}
+
+		name, err := request.RequireString("name")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: name: %w", err)
+		}
+
+		expression, err := request.RequireString("condition")
+		if err != nil {
+			return nil, fmt.Errorf("missing required parameter: condition: %w", err)
+		}
+		condition, err := k8s.ParseCondition(expression)
+		if err != nil {
+			return nil, err
+		}
+
+		namespace := request.GetString("namespace", "")
+		timeoutSeconds := request.GetInt("timeout_seconds", int(k8s.DefaultWaitTimeout.Seconds()))
+		if timeoutSeconds <= 0 || timeoutSeconds > MaxWatchSeconds {
+			return nil, fmt.Errorf("invalid timeout_seconds value: %d, must be between 1 and %d", timeoutSeconds, MaxWatchSeconds)
+		}
+
+		result, err := client.WaitForCondition(ctx, resourceType, name, namespace, condition, time.Duration(timeoutSeconds)*time.Second)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonResponse, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize response: %w", err)
+		}
+
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}